@@ -18,6 +18,27 @@ func (acc *Account) ToBase58() string {
 	return base58.Encode(acc.PublicKey)
 }
 
+func (acc *Account) GetPublicKey() PublicKey {
+	var pk PublicKey
+	copy(pk[:], acc.PublicKey)
+	return pk
+}
+
+func (acc *Account) Sign(message []byte) []byte {
+	priv := ed25519.NewKeyFromSeed(acc.SecretKey)
+	return ed25519.Sign(priv, message)
+}
+
+/*
+校验ed25519签名
+*/
+func VerifyMessage(pubkey PublicKey, message []byte, signature []byte) bool {
+	if len(signature) != ed25519.SignatureSize {
+		return false
+	}
+	return ed25519.Verify(pubkey[:], message, signature)
+}
+
 func NewAccount() (*Account, error) {
 	pub, priv, err := ed25519.GenerateKey(rand.Reader)
 	if err != nil {
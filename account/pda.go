@@ -0,0 +1,64 @@
+package account
+
+/*
+func：
+author： flynn
+date: 2020-08-03
+fork: https://github.com/solana-labs/solana-web3.js/src/publickey.js
+*/
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+)
+
+const (
+	MaxSeedLength = 32
+	MaxSeeds      = 16
+)
+
+var ErrInvalidSeeds = errors.New("invalid seeds, address must fall off the curve")
+
+/*
+生成Program Derived Address：sha256(seeds || programId || "ProgramDerivedAddress")，结果必须不在ed25519曲线上
+*/
+func CreateProgramAddress(seeds [][]byte, programId PublicKey) (PublicKey, error) {
+	var pk PublicKey
+	if len(seeds) > MaxSeeds {
+		return pk, fmt.Errorf("seeds count is %d, big than max seeds %d", len(seeds), MaxSeeds)
+	}
+	h := sha256.New()
+	for _, seed := range seeds {
+		if len(seed) > MaxSeedLength {
+			return pk, fmt.Errorf("seed length is %d, big than max seed length %d", len(seed), MaxSeedLength)
+		}
+		h.Write(seed)
+	}
+	h.Write(programId[:])
+	h.Write([]byte("ProgramDerivedAddress"))
+	copy(pk[:], h.Sum(nil))
+	if pk.IsOnCurve() {
+		return PublicKey{}, ErrInvalidSeeds
+	}
+	return pk, nil
+}
+
+/*
+从255开始递减bump，返回第一个有效的PDA及其bump
+*/
+func FindProgramAddress(seeds [][]byte, programId PublicKey) (PublicKey, uint8, error) {
+	seedsWithBump := make([][]byte, len(seeds)+1)
+	copy(seedsWithBump, seeds)
+	for bump := 255; bump > 0; bump-- {
+		seedsWithBump[len(seeds)] = []byte{byte(bump)}
+		pk, err := CreateProgramAddress(seedsWithBump, programId)
+		if err == ErrInvalidSeeds {
+			continue
+		}
+		if err != nil {
+			return PublicKey{}, 0, err
+		}
+		return pk, uint8(bump), nil
+	}
+	return PublicKey{}, 0, errors.New("unable to find a viable program address bump")
+}
@@ -0,0 +1,165 @@
+package account
+
+/*
+func：
+author： flynn
+date: 2020-08-03
+fork: https://github.com/solana-labs/solana-web3.js/src/publickey.js
+*/
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/btcsuite/btcutil/base58"
+)
+
+/*
+公钥长度
+*/
+const PublicKeySize = 32
+
+/*
+solana的公钥（地址），固定32字节
+*/
+type PublicKey [PublicKeySize]byte
+
+func PublicKeyFromBase58(s string) (PublicKey, error) {
+	var pk PublicKey
+	if s == "" {
+		return pk, errors.New("public key is null")
+	}
+	data := base58.Decode(s)
+	// btcutil的base58遇到非法字符时返回空
+	if len(data) == 0 {
+		return pk, fmt.Errorf("public key [%s] is not valid base58", s)
+	}
+	if len(data) != PublicKeySize {
+		return pk, fmt.Errorf("public key [%s] length is %d, not equal %d", s, len(data), PublicKeySize)
+	}
+	copy(pk[:], data)
+	return pk, nil
+}
+
+/*
+仅用于常量，解析失败直接panic
+*/
+func MustPublicKeyFromBase58(s string) PublicKey {
+	pk, err := PublicKeyFromBase58(s)
+	if err != nil {
+		panic(err)
+	}
+	return pk
+}
+
+func PublicKeyFromBytes(data []byte) (PublicKey, error) {
+	var pk PublicKey
+	if len(data) != PublicKeySize {
+		return pk, fmt.Errorf("public key length is %d, not equal %d", len(data), PublicKeySize)
+	}
+	copy(pk[:], data)
+	return pk, nil
+}
+
+func (pk PublicKey) String() string {
+	return base58.Encode(pk[:])
+}
+
+func (pk PublicKey) ToBase58() string {
+	return pk.String()
+}
+
+func (pk PublicKey) Bytes() []byte {
+	data := make([]byte, PublicKeySize)
+	copy(data, pk[:])
+	return data
+}
+
+func (pk PublicKey) Equals(other PublicKey) bool {
+	return pk == other
+}
+
+func (pk PublicKey) IsZero() bool {
+	return pk == PublicKey{}
+}
+
+/*
+判断公钥是否为ed25519曲线上的点，PDA地址必须不在曲线上
+*/
+func (pk PublicKey) IsOnCurve() bool {
+	return isOnCurve(pk[:])
+}
+
+func (pk PublicKey) MarshalText() ([]byte, error) {
+	return []byte(pk.String()), nil
+}
+
+func (pk *PublicKey) UnmarshalText(data []byte) error {
+	p, err := PublicKeyFromBase58(string(data))
+	if err != nil {
+		return err
+	}
+	*pk = p
+	return nil
+}
+
+func (pk PublicKey) MarshalJSON() ([]byte, error) {
+	return json.Marshal(pk.String())
+}
+
+func (pk *PublicKey) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("unmarshal public key error,Err=%v", err)
+	}
+	return pk.UnmarshalText([]byte(s))
+}
+
+/*
+ed25519曲线参数: p = 2^255 - 19, d = -121665/121666
+*/
+var (
+	curveP = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(19))
+	curveD = func() *big.Int {
+		d := new(big.Int).ModInverse(big.NewInt(121666), curveP)
+		d.Mul(d, big.NewInt(-121665))
+		return d.Mod(d, curveP)
+	}()
+	curveHalfP = new(big.Int).Rsh(new(big.Int).Sub(curveP, big.NewInt(1)), 1)
+)
+
+/*
+与curve25519-dalek的CompressedEdwardsY::decompress一致：
+x^2 = (y^2 - 1) / (d*y^2 + 1)，有平方根即在曲线上
+*/
+func isOnCurve(data []byte) bool {
+	if len(data) != PublicKeySize {
+		return false
+	}
+	// 小端序，最高位是x的符号位
+	le := make([]byte, PublicKeySize)
+	for i := 0; i < PublicKeySize; i++ {
+		le[i] = data[PublicKeySize-1-i]
+	}
+	le[0] &= 0x7f
+	y := new(big.Int).SetBytes(le)
+	y.Mod(y, curveP)
+
+	yy := new(big.Int).Mul(y, y)
+	yy.Mod(yy, curveP)
+	u := new(big.Int).Sub(yy, big.NewInt(1))
+	u.Mod(u, curveP)
+	v := new(big.Int).Mul(curveD, yy)
+	v.Add(v, big.NewInt(1))
+	v.Mod(v, curveP)
+
+	xx := new(big.Int).ModInverse(v, curveP)
+	xx.Mul(xx, u)
+	xx.Mod(xx, curveP)
+	if xx.Sign() == 0 {
+		return true
+	}
+	// 欧拉判别法
+	return new(big.Int).Exp(xx, curveHalfP, curveP).Cmp(big.NewInt(1)) == 0
+}
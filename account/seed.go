@@ -0,0 +1,34 @@
+package account
+
+/*
+func：
+author： flynn
+date: 2020-08-03
+fork: https://github.com/solana-labs/solana-web3.js/src/publickey.js
+*/
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+)
+
+/*
+由base公钥+字符串seed+owner派生地址：sha256(base || seed || owner)
+*/
+func CreateWithSeed(base PublicKey, seed string, owner PublicKey) (PublicKey, error) {
+	var pk PublicKey
+	if len(seed) > MaxSeedLength {
+		return pk, fmt.Errorf("seed length is %d, big than max seed length %d", len(seed), MaxSeedLength)
+	}
+	// 与solana runtime一致，owner不能以PDA标记结尾
+	if bytes.HasSuffix(owner[:], []byte("ProgramDerivedAddress")) {
+		return pk, errors.New("illegal owner, owner can not end with ProgramDerivedAddress")
+	}
+	h := sha256.New()
+	h.Write(base[:])
+	h.Write([]byte(seed))
+	h.Write(owner[:])
+	copy(pk[:], h.Sum(nil))
+	return pk, nil
+}
@@ -0,0 +1,142 @@
+package addresslookuptable
+
+/*
+func：
+author： flynn
+date: 2020-08-03
+fork: https://github.com/solana-labs/solana-web3.js/src/programs/address-lookup-table/index.ts
+*/
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"github.com/JFJun/solana-go/account"
+	"github.com/JFJun/solana-go/systemprogram"
+	"github.com/JFJun/solana-go/transaction"
+)
+
+var ProgramId = account.MustPublicKeyFromBase58("AddressLookupTab1e1111111111111111111111111")
+
+const (
+	InstructionCreateLookupTable uint32 = iota
+	InstructionFreezeLookupTable
+	InstructionExtendLookupTable
+	InstructionDeactivateLookupTable
+	InstructionCloseLookupTable
+)
+
+type CreateLookupTableParams struct {
+	Authority  account.PublicKey
+	Payer      account.PublicKey
+	RecentSlot uint64
+}
+
+type ExtendLookupTableParams struct {
+	LookupTable account.PublicKey
+	Authority   account.PublicKey
+	// 为空时不需要支付租金（表空间已足够）
+	Payer     *account.PublicKey
+	Addresses []account.PublicKey
+}
+
+type FreezeLookupTableParams struct {
+	LookupTable account.PublicKey
+	Authority   account.PublicKey
+}
+
+type DeactivateLookupTableParams struct {
+	LookupTable account.PublicKey
+	Authority   account.PublicKey
+}
+
+type CloseLookupTableParams struct {
+	LookupTable account.PublicKey
+	Authority   account.PublicKey
+	Recipient   account.PublicKey
+}
+
+/*
+查找表地址由authority和recent slot派生：seeds = [authority, recentSlot(u64 le)]
+*/
+func FindLookupTableAddress(authority account.PublicKey, recentSlot uint64) (account.PublicKey, uint8, error) {
+	slot := make([]byte, 8)
+	binary.LittleEndian.PutUint64(slot, recentSlot)
+	return account.FindProgramAddress([][]byte{authority[:], slot}, ProgramId)
+}
+
+/*
+返回指令以及新查找表的地址
+*/
+func NewCreateLookupTable(params CreateLookupTableParams) (transaction.ITransactionInstruction, account.PublicKey, error) {
+	lookupTable, bump, err := FindLookupTableAddress(params.Authority, params.RecentSlot)
+	if err != nil {
+		return nil, account.PublicKey{}, err
+	}
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, InstructionCreateLookupTable)
+	binary.Write(buf, binary.LittleEndian, params.RecentSlot)
+	buf.WriteByte(bump)
+	ins, err := transaction.NewTransactionInstruction(ProgramId, []*transaction.AccountMeta{
+		transaction.NewAccountMeta(lookupTable, false, true),
+		transaction.NewAccountMeta(params.Authority, true, false),
+		transaction.NewAccountMeta(params.Payer, true, true),
+		transaction.NewAccountMeta(systemprogram.ProgramId, false, false),
+	}, buf.Bytes())
+	if err != nil {
+		return nil, account.PublicKey{}, err
+	}
+	return ins, lookupTable, nil
+}
+
+func NewExtendLookupTable(params ExtendLookupTableParams) (transaction.ITransactionInstruction, error) {
+	if len(params.Addresses) == 0 {
+		return nil, errors.New("extend lookup table addresses is empty")
+	}
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, InstructionExtendLookupTable)
+	binary.Write(buf, binary.LittleEndian, uint64(len(params.Addresses)))
+	for _, addr := range params.Addresses {
+		buf.Write(addr[:])
+	}
+	keys := []*transaction.AccountMeta{
+		transaction.NewAccountMeta(params.LookupTable, false, true),
+		transaction.NewAccountMeta(params.Authority, true, false),
+	}
+	if params.Payer != nil {
+		keys = append(keys,
+			transaction.NewAccountMeta(*params.Payer, true, true),
+			transaction.NewAccountMeta(systemprogram.ProgramId, false, false),
+		)
+	}
+	return transaction.NewTransactionInstruction(ProgramId, keys, buf.Bytes())
+}
+
+func NewFreezeLookupTable(params FreezeLookupTableParams) (transaction.ITransactionInstruction, error) {
+	return newAuthorityInstruction(InstructionFreezeLookupTable, params.LookupTable, params.Authority)
+}
+
+func NewDeactivateLookupTable(params DeactivateLookupTableParams) (transaction.ITransactionInstruction, error) {
+	return newAuthorityInstruction(InstructionDeactivateLookupTable, params.LookupTable, params.Authority)
+}
+
+/*
+关闭查找表前需要先deactivate并等待冷却期结束
+*/
+func NewCloseLookupTable(params CloseLookupTableParams) (transaction.ITransactionInstruction, error) {
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, InstructionCloseLookupTable)
+	return transaction.NewTransactionInstruction(ProgramId, []*transaction.AccountMeta{
+		transaction.NewAccountMeta(params.LookupTable, false, true),
+		transaction.NewAccountMeta(params.Authority, true, false),
+		transaction.NewAccountMeta(params.Recipient, false, true),
+	}, buf.Bytes())
+}
+
+func newAuthorityInstruction(index uint32, lookupTable, authority account.PublicKey) (transaction.ITransactionInstruction, error) {
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, index)
+	return transaction.NewTransactionInstruction(ProgramId, []*transaction.AccountMeta{
+		transaction.NewAccountMeta(lookupTable, false, true),
+		transaction.NewAccountMeta(authority, true, false),
+	}, buf.Bytes())
+}
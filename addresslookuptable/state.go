@@ -0,0 +1,98 @@
+package addresslookuptable
+
+/*
+func：
+author： flynn
+date: 2020-08-03
+fork: https://github.com/solana-labs/solana-web3.js/src/programs/address-lookup-table/state.ts
+*/
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/JFJun/solana-go/account"
+	"github.com/JFJun/solana-go/rpc"
+	"github.com/JFJun/solana-go/transaction"
+)
+
+/*
+查找表账户meta部分的固定长度，之后紧跟地址列表
+*/
+const LookupTableMetaSize = 56
+
+/*
+deactivation slot为u64最大值表示查找表仍然有效
+*/
+const ActiveDeactivationSlot = ^uint64(0)
+
+type LookupTableState struct {
+	DeactivationSlot           uint64
+	LastExtendedSlot           uint64
+	LastExtendedSlotStartIndex uint8
+	Authority                  *account.PublicKey // 为nil表示已冻结
+	Addresses                  []account.PublicKey
+}
+
+func (state *LookupTableState) IsActive() bool {
+	return state.DeactivationSlot == ActiveDeactivationSlot
+}
+
+func (state *LookupTableState) ToAccount(key account.PublicKey) *transaction.AddressLookupTableAccount {
+	return &transaction.AddressLookupTableAccount{
+		Key:       key,
+		Addresses: state.Addresses,
+	}
+}
+
+/*
+解析链上查找表账户数据：
+u32 类型 | u64 deactivation slot | u64 last extended slot | u8 start index | Option<Pubkey> authority | 填充 | 地址列表
+*/
+func DeserializeLookupTable(data []byte) (*LookupTableState, error) {
+	if len(data) < LookupTableMetaSize {
+		return nil, fmt.Errorf("lookup table data length is %d, less than %d", len(data), LookupTableMetaSize)
+	}
+	if typeIndex := binary.LittleEndian.Uint32(data[0:4]); typeIndex != 1 {
+		return nil, fmt.Errorf("account is not a lookup table, type=%d", typeIndex)
+	}
+	if (len(data)-LookupTableMetaSize)%account.PublicKeySize != 0 {
+		return nil, errors.New("lookup table addresses length is invalid")
+	}
+	state := &LookupTableState{
+		DeactivationSlot:           binary.LittleEndian.Uint64(data[4:12]),
+		LastExtendedSlot:           binary.LittleEndian.Uint64(data[12:20]),
+		LastExtendedSlotStartIndex: data[20],
+	}
+	if data[21] == 1 {
+		var authority account.PublicKey
+		copy(authority[:], data[22:54])
+		state.Authority = &authority
+	}
+	for offset := LookupTableMetaSize; offset < len(data); offset += account.PublicKeySize {
+		var addr account.PublicKey
+		copy(addr[:], data[offset:offset+account.PublicKeySize])
+		state.Addresses = append(state.Addresses, addr)
+	}
+	return state, nil
+}
+
+/*
+通过rpc获取查找表，可直接用于v0交易的编译和解析
+*/
+func GetLookupTable(client *rpc.RpcClient, key account.PublicKey) (*transaction.AddressLookupTableAccount, error) {
+	info, err := client.GetAccountInfo(key)
+	if err != nil {
+		return nil, err
+	}
+	if info == nil {
+		return nil, fmt.Errorf("lookup table [%s] is not found", key.String())
+	}
+	if info.Owner != ProgramId {
+		return nil, fmt.Errorf("account [%s] is not owned by address lookup table program", key.String())
+	}
+	state, err := DeserializeLookupTable(info.Data)
+	if err != nil {
+		return nil, err
+	}
+	return state.ToAccount(key), nil
+}
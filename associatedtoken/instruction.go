@@ -0,0 +1,168 @@
+package associatedtoken
+
+/*
+func：
+author： flynn
+date: 2020-08-03
+fork: https://github.com/solana-labs/solana-program-library/token/js/src/instructions/associatedTokenAccount.ts
+*/
+import (
+	"github.com/JFJun/solana-go/account"
+	"github.com/JFJun/solana-go/systemprogram"
+	"github.com/JFJun/solana-go/token"
+	"github.com/JFJun/solana-go/transaction"
+)
+
+/*
+associated token account program的id
+*/
+var ProgramId = account.MustPublicKeyFromBase58("ATokenGPvbdGVxr1b2hvZbsiqW5xWH25efTNsLJA8knL")
+
+const (
+	InstructionCreate uint8 = iota
+	InstructionCreateIdempotent
+	InstructionRecoverNested
+)
+
+/*
+TokenProgram为空时使用spl token program
+*/
+type CreateParams struct {
+	Payer        account.PublicKey
+	Wallet       account.PublicKey
+	Mint         account.PublicKey
+	TokenProgram account.PublicKey
+}
+
+/*
+恢复误转到ATA自己名下的ATA（nested）中的token：
+Wallet的OwnerMint ATA持有的NestedMint ATA中的token转回Wallet的NestedMint ATA，并关闭nested账户
+*/
+type RecoverNestedParams struct {
+	Wallet       account.PublicKey
+	OwnerMint    account.PublicKey
+	NestedMint   account.PublicKey
+	TokenProgram account.PublicKey
+}
+
+/*
+一次完成向钱包地址转账：目标ATA不存在时创建（idempotent），然后TransferChecked
+*/
+type TransferToWalletParams struct {
+	Payer        account.PublicKey // 支付创建ATA的租金
+	Owner        account.PublicKey // 付款钱包，Source为它的ATA
+	ToWallet     account.PublicKey
+	Mint         account.PublicKey
+	Amount       uint64
+	Decimals     uint8
+	MultiSigners []account.PublicKey
+	TokenProgram account.PublicKey
+}
+
+/*
+ATA地址：seeds = [wallet, tokenProgram, mint]
+*/
+func FindAssociatedTokenAddress(wallet, mint, tokenProgram account.PublicKey) (account.PublicKey, uint8, error) {
+	tokenProgram = tokenProgramOrDefault(tokenProgram)
+	return account.FindProgramAddress([][]byte{wallet[:], tokenProgram[:], mint[:]}, ProgramId)
+}
+
+/*
+ATA已存在时交易会失败
+*/
+func NewCreateAssociatedTokenAccount(params CreateParams) (transaction.ITransactionInstruction, error) {
+	return newCreateInstruction(params, []byte{InstructionCreate})
+}
+
+/*
+ATA已存在（且owner和mint一致）时不报错，适合打款时无条件带上
+*/
+func NewCreateAssociatedTokenAccountIdempotent(params CreateParams) (transaction.ITransactionInstruction, error) {
+	return newCreateInstruction(params, []byte{InstructionCreateIdempotent})
+}
+
+func newCreateInstruction(params CreateParams, data []byte) (transaction.ITransactionInstruction, error) {
+	tokenProgram := tokenProgramOrDefault(params.TokenProgram)
+	ata, _, err := FindAssociatedTokenAddress(params.Wallet, params.Mint, tokenProgram)
+	if err != nil {
+		return nil, err
+	}
+	return transaction.NewTransactionInstruction(ProgramId, []*transaction.AccountMeta{
+		transaction.NewAccountMeta(params.Payer, true, true),
+		transaction.NewAccountMeta(ata, false, true),
+		transaction.NewAccountMeta(params.Wallet, false, false),
+		transaction.NewAccountMeta(params.Mint, false, false),
+		transaction.NewAccountMeta(systemprogram.ProgramId, false, false),
+		transaction.NewAccountMeta(tokenProgram, false, false),
+	}, data)
+}
+
+func NewRecoverNested(params RecoverNestedParams) (transaction.ITransactionInstruction, error) {
+	tokenProgram := tokenProgramOrDefault(params.TokenProgram)
+	ownerAta, _, err := FindAssociatedTokenAddress(params.Wallet, params.OwnerMint, tokenProgram)
+	if err != nil {
+		return nil, err
+	}
+	nestedAta, _, err := FindAssociatedTokenAddress(ownerAta, params.NestedMint, tokenProgram)
+	if err != nil {
+		return nil, err
+	}
+	destinationAta, _, err := FindAssociatedTokenAddress(params.Wallet, params.NestedMint, tokenProgram)
+	if err != nil {
+		return nil, err
+	}
+	return transaction.NewTransactionInstruction(ProgramId, []*transaction.AccountMeta{
+		transaction.NewAccountMeta(nestedAta, false, true),
+		transaction.NewAccountMeta(params.NestedMint, false, false),
+		transaction.NewAccountMeta(destinationAta, false, true),
+		transaction.NewAccountMeta(ownerAta, false, false),
+		transaction.NewAccountMeta(params.OwnerMint, false, false),
+		transaction.NewAccountMeta(params.Wallet, true, true),
+		transaction.NewAccountMeta(tokenProgram, false, false),
+	}, []byte{InstructionRecoverNested})
+}
+
+/*
+返回[创建目标ATA(idempotent), TransferChecked]两条指令
+*/
+func NewTransferToWallet(params TransferToWalletParams) ([]transaction.ITransactionInstruction, error) {
+	tokenProgram := tokenProgramOrDefault(params.TokenProgram)
+	source, _, err := FindAssociatedTokenAddress(params.Owner, params.Mint, tokenProgram)
+	if err != nil {
+		return nil, err
+	}
+	destination, _, err := FindAssociatedTokenAddress(params.ToWallet, params.Mint, tokenProgram)
+	if err != nil {
+		return nil, err
+	}
+	create, err := NewCreateAssociatedTokenAccountIdempotent(CreateParams{
+		Payer:        params.Payer,
+		Wallet:       params.ToWallet,
+		Mint:         params.Mint,
+		TokenProgram: tokenProgram,
+	})
+	if err != nil {
+		return nil, err
+	}
+	transfer, err := token.NewTransferChecked(token.TransferCheckedParams{
+		Source:       source,
+		Mint:         params.Mint,
+		Destination:  destination,
+		Owner:        params.Owner,
+		Amount:       params.Amount,
+		Decimals:     params.Decimals,
+		MultiSigners: params.MultiSigners,
+		ProgramId:    tokenProgram,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return []transaction.ITransactionInstruction{create, transfer}, nil
+}
+
+func tokenProgramOrDefault(tokenProgram account.PublicKey) account.PublicKey {
+	if tokenProgram.IsZero() {
+		return token.ProgramId
+	}
+	return tokenProgram
+}
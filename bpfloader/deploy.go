@@ -0,0 +1,162 @@
+package bpfloader
+
+/*
+func：
+author： flynn
+date: 2020-08-03
+fork: https://github.com/solana-labs/solana/cli/src/program.rs
+*/
+import (
+	"errors"
+	"fmt"
+	"github.com/JFJun/solana-go/account"
+	"github.com/JFJun/solana-go/transaction"
+	"io/ioutil"
+)
+
+type CreateBufferParams struct {
+	Payer      account.PublicKey
+	Buffer     account.PublicKey
+	Authority  account.PublicKey
+	Lamports   uint64 // 需要包含BufferAccountSize(ProgramLen)的免租金额
+	ProgramLen int
+}
+
+type DeployProgramParams struct {
+	Payer      account.PublicKey
+	Program    account.PublicKey
+	Buffer     account.PublicKey
+	Authority  account.PublicKey
+	Lamports   uint64 // program账户的免租金额（ProgramAccountSize）
+	MaxDataLen uint64
+}
+
+/*
+CreateAccount(space=BufferAccountSize, owner=loader) + InitializeBuffer，需要Payer和Buffer签名
+*/
+func NewCreateBuffer(params CreateBufferParams) ([]transaction.ITransactionInstruction, error) {
+	create, err := transaction.NewCreateAccount(transaction.CreateAccountParams{
+		From:       params.Payer,
+		NewAccount: params.Buffer,
+		Lamports:   params.Lamports,
+		Space:      BufferAccountSize(params.ProgramLen),
+		ProgramId:  ProgramId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	initialize, err := NewInitializeBuffer(InitializeBufferParams{
+		Buffer:    params.Buffer,
+		Authority: params.Authority,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return []transaction.ITransactionInstruction{create, initialize}, nil
+}
+
+/*
+CreateAccount(space=ProgramAccountSize, owner=loader) + DeployWithMaxDataLen，需要Payer、Program和Authority签名
+*/
+func NewDeployProgram(params DeployProgramParams) ([]transaction.ITransactionInstruction, error) {
+	create, err := transaction.NewCreateAccount(transaction.CreateAccountParams{
+		From:       params.Payer,
+		NewAccount: params.Program,
+		Lamports:   params.Lamports,
+		Space:      ProgramAccountSize,
+		ProgramId:  ProgramId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	deploy, err := NewDeployWithMaxDataLen(DeployWithMaxDataLenParams{
+		Payer:      params.Payer,
+		Program:    params.Program,
+		Buffer:     params.Buffer,
+		Authority:  params.Authority,
+		MaxDataLen: params.MaxDataLen,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return []transaction.ITransactionInstruction{create, deploy}, nil
+}
+
+type WriteTransactionsParams struct {
+	Payer           account.PublicKey
+	Buffer          account.PublicKey
+	Authority       account.PublicKey
+	Data            []byte // 程序的ELF数据
+	RecentBlockHash string
+}
+
+/*
+把程序数据按块写入buffer，每块大小保证交易不超过PACK_DATA_SIZE
+返回未签名的交易，需要Payer和Authority签名，交易之间没有顺序依赖
+*/
+func NewWriteTransactions(params WriteTransactionsParams) ([]*transaction.Transaction, error) {
+	if len(params.Data) == 0 {
+		return nil, errors.New("program data is empty")
+	}
+	chunkSize, err := WriteChunkSize(params.Payer, params.Buffer, params.Authority, params.RecentBlockHash)
+	if err != nil {
+		return nil, err
+	}
+	var txs []*transaction.Transaction
+	for offset := 0; offset < len(params.Data); offset += chunkSize {
+		end := offset + chunkSize
+		if end > len(params.Data) {
+			end = len(params.Data)
+		}
+		tx, err := newWriteTransaction(params.Payer, params.Buffer, params.Authority, uint32(offset), params.Data[offset:end], params.RecentBlockHash)
+		if err != nil {
+			return nil, err
+		}
+		txs = append(txs, tx)
+	}
+	return txs, nil
+}
+
+/*
+读取编译好的.so文件并生成写入交易
+*/
+func NewWriteTransactionsFromFile(path string, params WriteTransactionsParams) ([]*transaction.Transaction, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read program file error,Err=%v", err)
+	}
+	params.Data = data
+	return NewWriteTransactions(params)
+}
+
+/*
+单个Write交易可携带的最大字节数：用空数据的交易计算固定开销
+数据长度>=128时指令数据的shortvec长度多占一个字节
+*/
+func WriteChunkSize(payer, buffer, authority account.PublicKey, recentBlockHash string) (int, error) {
+	tx, err := newWriteTransaction(payer, buffer, authority, 0, []byte{}, recentBlockHash)
+	if err != nil {
+		return 0, err
+	}
+	wireTx, err := tx.SerializeWithConfig(transaction.SerializeConfig{RequireAllSignatures: false})
+	if err != nil {
+		return 0, err
+	}
+	return transaction.PACK_DATA_SIZE - len(wireTx) - 1, nil
+}
+
+func newWriteTransaction(payer, buffer, authority account.PublicKey, offset uint32, data []byte, recentBlockHash string) (*transaction.Transaction, error) {
+	write, err := NewWrite(WriteParams{
+		Buffer:    buffer,
+		Authority: authority,
+		Offset:    offset,
+		Bytes:     data,
+	})
+	if err != nil {
+		return nil, err
+	}
+	tx := transaction.NewTransaction(recentBlockHash)
+	tx.FeePayer = payer
+	tx.SetInstructions(write)
+	return tx, nil
+}
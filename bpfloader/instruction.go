@@ -0,0 +1,247 @@
+package bpfloader
+
+/*
+func：
+author： flynn
+date: 2020-08-03
+fork: https://github.com/solana-labs/solana/sdk/program/src/loader_upgradeable_instruction.rs
+*/
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"github.com/JFJun/solana-go/account"
+	"github.com/JFJun/solana-go/systemprogram"
+	"github.com/JFJun/solana-go/sysvar"
+	"github.com/JFJun/solana-go/transaction"
+)
+
+/*
+BPF upgradeable loader的id
+*/
+var ProgramId = account.MustPublicKeyFromBase58("BPFLoaderUpgradeab1e11111111111111111111111")
+
+/*
+指令序号，与UpgradeableLoaderInstruction的顺序一致
+*/
+const (
+	InstructionInitializeBuffer uint32 = iota
+	InstructionWrite
+	InstructionDeployWithMaxDataLen
+	InstructionUpgrade
+	InstructionSetAuthority
+	InstructionClose
+	InstructionExtendProgram
+	InstructionSetAuthorityChecked
+)
+
+/*
+UpgradeableLoaderState各状态的元数据长度，之后紧跟程序数据
+buffer：u32 type | Option<Pubkey> authority
+program：u32 type | programdata address
+programdata：u32 type | u64 slot | Option<Pubkey> upgrade authority
+*/
+const (
+	BufferMetadataSize      = 37
+	ProgramAccountSize      = 36
+	ProgramDataMetadataSize = 45
+)
+
+func BufferAccountSize(programLen int) uint64 {
+	return uint64(BufferMetadataSize + programLen)
+}
+
+func ProgramDataAccountSize(maxDataLen uint64) uint64 {
+	return ProgramDataMetadataSize + maxDataLen
+}
+
+/*
+programdata地址：seeds = [program id]
+*/
+func FindProgramDataAddress(programId account.PublicKey) (account.PublicKey, uint8, error) {
+	return account.FindProgramAddress([][]byte{programId[:]}, ProgramId)
+}
+
+type InitializeBufferParams struct {
+	Buffer    account.PublicKey
+	Authority account.PublicKey
+}
+
+type WriteParams struct {
+	Buffer    account.PublicKey
+	Authority account.PublicKey
+	Offset    uint32
+	Bytes     []byte
+}
+
+type DeployWithMaxDataLenParams struct {
+	Payer      account.PublicKey
+	Program    account.PublicKey
+	Buffer     account.PublicKey
+	Authority  account.PublicKey // 升级权限，同时需要是buffer的authority
+	MaxDataLen uint64
+}
+
+type UpgradeParams struct {
+	Program   account.PublicKey
+	Buffer    account.PublicKey
+	Spill     account.PublicKey // 接收buffer中的lamports
+	Authority account.PublicKey
+}
+
+/*
+Account为buffer或programdata；NewAuthority为nil时程序变为不可升级
+*/
+type SetAuthorityParams struct {
+	Account          account.PublicKey
+	CurrentAuthority account.PublicKey
+	NewAuthority     *account.PublicKey
+}
+
+/*
+关闭buffer时Program为nil；关闭programdata时需要传入Program
+*/
+type CloseParams struct {
+	Account   account.PublicKey
+	Recipient account.PublicKey
+	Authority account.PublicKey
+	Program   *account.PublicKey
+}
+
+/*
+Payer为nil时programdata需要已有足够的lamports
+*/
+type ExtendProgramParams struct {
+	Program         account.PublicKey
+	Payer           *account.PublicKey
+	AdditionalBytes uint32
+}
+
+func NewInitializeBuffer(params InitializeBufferParams) (transaction.ITransactionInstruction, error) {
+	return newLoaderInstruction([]*transaction.AccountMeta{
+		transaction.NewAccountMeta(params.Buffer, false, true),
+		transaction.NewAccountMeta(params.Authority, false, false),
+	}, encodeIndex(InstructionInitializeBuffer))
+}
+
+/*
+bytes为bincode的Vec<u8>：u64长度 + 数据
+*/
+func NewWrite(params WriteParams) (transaction.ITransactionInstruction, error) {
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, InstructionWrite)
+	binary.Write(buf, binary.LittleEndian, params.Offset)
+	binary.Write(buf, binary.LittleEndian, uint64(len(params.Bytes)))
+	buf.Write(params.Bytes)
+	return newLoaderInstruction([]*transaction.AccountMeta{
+		transaction.NewAccountMeta(params.Buffer, false, true),
+		transaction.NewAccountMeta(params.Authority, true, false),
+	}, buf.Bytes())
+}
+
+/*
+program账户需要预先创建（ProgramAccountSize，owner为loader），programdata由loader创建
+*/
+func NewDeployWithMaxDataLen(params DeployWithMaxDataLenParams) (transaction.ITransactionInstruction, error) {
+	programData, _, err := FindProgramDataAddress(params.Program)
+	if err != nil {
+		return nil, err
+	}
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, InstructionDeployWithMaxDataLen)
+	binary.Write(buf, binary.LittleEndian, params.MaxDataLen)
+	return newLoaderInstruction([]*transaction.AccountMeta{
+		transaction.NewAccountMeta(params.Payer, true, true),
+		transaction.NewAccountMeta(programData, false, true),
+		transaction.NewAccountMeta(params.Program, false, true),
+		transaction.NewAccountMeta(params.Buffer, false, true),
+		transaction.NewAccountMeta(sysvar.RentPubkey, false, false),
+		transaction.NewAccountMeta(sysvar.ClockPubkey, false, false),
+		transaction.NewAccountMeta(systemprogram.ProgramId, false, false),
+		transaction.NewAccountMeta(params.Authority, true, false),
+	}, buf.Bytes())
+}
+
+func NewUpgrade(params UpgradeParams) (transaction.ITransactionInstruction, error) {
+	programData, _, err := FindProgramDataAddress(params.Program)
+	if err != nil {
+		return nil, err
+	}
+	return newLoaderInstruction([]*transaction.AccountMeta{
+		transaction.NewAccountMeta(programData, false, true),
+		transaction.NewAccountMeta(params.Program, false, true),
+		transaction.NewAccountMeta(params.Buffer, false, true),
+		transaction.NewAccountMeta(params.Spill, false, true),
+		transaction.NewAccountMeta(sysvar.RentPubkey, false, false),
+		transaction.NewAccountMeta(sysvar.ClockPubkey, false, false),
+		transaction.NewAccountMeta(params.Authority, true, false),
+	}, encodeIndex(InstructionUpgrade))
+}
+
+func NewSetAuthority(params SetAuthorityParams) (transaction.ITransactionInstruction, error) {
+	keys := []*transaction.AccountMeta{
+		transaction.NewAccountMeta(params.Account, false, true),
+		transaction.NewAccountMeta(params.CurrentAuthority, true, false),
+	}
+	if params.NewAuthority != nil {
+		keys = append(keys, transaction.NewAccountMeta(*params.NewAuthority, false, false))
+	}
+	return newLoaderInstruction(keys, encodeIndex(InstructionSetAuthority))
+}
+
+/*
+与SetAuthority相同，但新的authority也需要签名，不能设置为不可升级
+*/
+func NewSetAuthorityChecked(params SetAuthorityParams) (transaction.ITransactionInstruction, error) {
+	if params.NewAuthority == nil {
+		return nil, errors.New("set authority checked new authority is null")
+	}
+	return newLoaderInstruction([]*transaction.AccountMeta{
+		transaction.NewAccountMeta(params.Account, false, true),
+		transaction.NewAccountMeta(params.CurrentAuthority, true, false),
+		transaction.NewAccountMeta(*params.NewAuthority, true, false),
+	}, encodeIndex(InstructionSetAuthorityChecked))
+}
+
+func NewClose(params CloseParams) (transaction.ITransactionInstruction, error) {
+	keys := []*transaction.AccountMeta{
+		transaction.NewAccountMeta(params.Account, false, true),
+		transaction.NewAccountMeta(params.Recipient, false, true),
+		transaction.NewAccountMeta(params.Authority, true, false),
+	}
+	if params.Program != nil {
+		keys = append(keys, transaction.NewAccountMeta(*params.Program, false, true))
+	}
+	return newLoaderInstruction(keys, encodeIndex(InstructionClose))
+}
+
+func NewExtendProgram(params ExtendProgramParams) (transaction.ITransactionInstruction, error) {
+	programData, _, err := FindProgramDataAddress(params.Program)
+	if err != nil {
+		return nil, err
+	}
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, InstructionExtendProgram)
+	binary.Write(buf, binary.LittleEndian, params.AdditionalBytes)
+	keys := []*transaction.AccountMeta{
+		transaction.NewAccountMeta(programData, false, true),
+		transaction.NewAccountMeta(params.Program, false, true),
+	}
+	if params.Payer != nil {
+		keys = append(keys,
+			transaction.NewAccountMeta(systemprogram.ProgramId, false, false),
+			transaction.NewAccountMeta(*params.Payer, true, true),
+		)
+	}
+	return newLoaderInstruction(keys, buf.Bytes())
+}
+
+func encodeIndex(index uint32) []byte {
+	data := make([]byte, 4)
+	binary.LittleEndian.PutUint32(data, index)
+	return data
+}
+
+func newLoaderInstruction(keys []*transaction.AccountMeta, data []byte) (transaction.ITransactionInstruction, error) {
+	return transaction.NewTransactionInstruction(ProgramId, keys, data)
+}
@@ -0,0 +1,268 @@
+package computebudget
+
+/*
+func：
+author： flynn
+date: 2020-08-03
+fork: https://github.com/solana-labs/solana-web3.js/src/programs/compute-budget.ts
+*/
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/JFJun/solana-go/account"
+	"github.com/JFJun/solana-go/transaction"
+	"math/bits"
+)
+
+/*
+compute budget program的id
+*/
+var ProgramId = account.MustPublicKeyFromBase58("ComputeBudget111111111111111111111111111111")
+
+const (
+	InstructionRequestUnitsDeprecated uint8 = iota
+	InstructionRequestHeapFrame
+	InstructionSetComputeUnitLimit
+	InstructionSetComputeUnitPrice
+	InstructionSetLoadedAccountsDataSizeLimit
+)
+
+/*
+precompile程序：每条指令数据的第一个字节是需要校验的签名数量，按签名收取手续费
+*/
+var (
+	ed25519ProgramId   = account.MustPublicKeyFromBase58("Ed25519SigVerify111111111111111111111111111")
+	secp256k1ProgramId = account.MustPublicKeyFromBase58("KeccakSecp256k11111111111111111111111111111")
+)
+
+/*
+未设置compute unit limit时，builtin程序的指令只预留MaxBuiltinAllocationComputeUnitLimit（reserve_minimal_cus_for_builtin_instructions）
+直接使用program id，避免依赖各个程序的包
+*/
+var builtinProgramIds = map[account.PublicKey]bool{
+	ProgramId: true,
+	account.MustPublicKeyFromBase58("11111111111111111111111111111111"):            true,
+	account.MustPublicKeyFromBase58("Vote111111111111111111111111111111111111111"): true,
+	account.MustPublicKeyFromBase58("Stake11111111111111111111111111111111111111"): true,
+	account.MustPublicKeyFromBase58("Config1111111111111111111111111111111111111"): true,
+	account.MustPublicKeyFromBase58("AddressLookupTab1e1111111111111111111111111"): true,
+	account.MustPublicKeyFromBase58("BPFLoader1111111111111111111111111111111111"): true,
+	account.MustPublicKeyFromBase58("BPFLoader2111111111111111111111111111111111"): true,
+	account.MustPublicKeyFromBase58("BPFLoaderUpgradeab1e11111111111111111111111"): true,
+	account.MustPublicKeyFromBase58("LoaderV411111111111111111111111111111111111"): true,
+	ed25519ProgramId:   true,
+	secp256k1ProgramId: true,
+}
+
+const (
+	DefaultInstructionComputeUnitLimit   = 200000
+	MaxBuiltinAllocationComputeUnitLimit = 3000
+	MaxComputeUnitLimit                  = 1400000
+	MinHeapFrameBytes                    = 32 * 1024
+	MaxHeapFrameBytes                    = 256 * 1024
+	MicroLamportsPerLamport              = 1000000
+	DefaultLamportsPerSignature          = 5000
+)
+
+/*
+为0的字段不生成对应指令
+*/
+type Params struct {
+	ComputeUnitLimit            uint32
+	ComputeUnitPrice            uint64 // 每个compute unit的价格，单位micro-lamports
+	HeapFrameBytes              uint32
+	LoadedAccountsDataSizeLimit uint32
+}
+
+func NewSetComputeUnitLimit(units uint32) (transaction.ITransactionInstruction, error) {
+	if units > MaxComputeUnitLimit {
+		return nil, fmt.Errorf("compute unit limit [%d] is greater than %d", units, MaxComputeUnitLimit)
+	}
+	return newU32Instruction(InstructionSetComputeUnitLimit, units)
+}
+
+func NewSetComputeUnitPrice(microLamports uint64) (transaction.ITransactionInstruction, error) {
+	data := make([]byte, 9)
+	data[0] = InstructionSetComputeUnitPrice
+	binary.LittleEndian.PutUint64(data[1:], microLamports)
+	return newComputeBudgetInstruction(data)
+}
+
+/*
+堆大小必须是1024的整数倍，范围[32K,256K]
+*/
+func NewRequestHeapFrame(bytes uint32) (transaction.ITransactionInstruction, error) {
+	if bytes%1024 != 0 || bytes < MinHeapFrameBytes || bytes > MaxHeapFrameBytes {
+		return nil, fmt.Errorf("heap frame bytes [%d] is not valid", bytes)
+	}
+	return newU32Instruction(InstructionRequestHeapFrame, bytes)
+}
+
+func NewSetLoadedAccountsDataSizeLimit(bytes uint32) (transaction.ITransactionInstruction, error) {
+	if bytes == 0 {
+		return nil, errors.New("loaded accounts data size limit is 0")
+	}
+	return newU32Instruction(InstructionSetLoadedAccountsDataSizeLimit, bytes)
+}
+
+/*
+按params生成指令，顺序：limit | price | heap | loaded accounts data size
+*/
+func NewInstructions(params Params) ([]transaction.ITransactionInstruction, error) {
+	var (
+		instructions []transaction.ITransactionInstruction
+		ins          transaction.ITransactionInstruction
+		err          error
+	)
+	if params.ComputeUnitLimit > 0 {
+		if ins, err = NewSetComputeUnitLimit(params.ComputeUnitLimit); err != nil {
+			return nil, err
+		}
+		instructions = append(instructions, ins)
+	}
+	if params.ComputeUnitPrice > 0 {
+		if ins, err = NewSetComputeUnitPrice(params.ComputeUnitPrice); err != nil {
+			return nil, err
+		}
+		instructions = append(instructions, ins)
+	}
+	if params.HeapFrameBytes > 0 {
+		if ins, err = NewRequestHeapFrame(params.HeapFrameBytes); err != nil {
+			return nil, err
+		}
+		instructions = append(instructions, ins)
+	}
+	if params.LoadedAccountsDataSizeLimit > 0 {
+		if ins, err = NewSetLoadedAccountsDataSizeLimit(params.LoadedAccountsDataSizeLimit); err != nil {
+			return nil, err
+		}
+		instructions = append(instructions, ins)
+	}
+	return instructions, nil
+}
+
+/*
+设置交易的ComputeBudgetInstructions，编译时放在指令最前面；Instructions中已有的compute budget指令会被移除（同类指令重复时交易会失败）
+durable nonce交易编译时AdvanceNonceAccount仍然是第一条指令
+*/
+func SetComputeBudget(tx *transaction.Transaction, params Params) error {
+	instructions, err := NewInstructions(params)
+	if err != nil {
+		return err
+	}
+	var rest []transaction.ITransactionInstruction
+	for _, ins := range tx.Instructions {
+		if ins.GetProgramId() != ProgramId {
+			rest = append(rest, ins)
+		}
+	}
+	tx.Instructions = rest
+	tx.ComputeBudgetInstructions = instructions
+	return nil
+}
+
+/*
+解析交易中的compute budget指令
+*/
+func ParseParams(instructions []transaction.ITransactionInstruction) (*Params, error) {
+	params := new(Params)
+	for _, ins := range instructions {
+		if ins.GetProgramId() != ProgramId {
+			continue
+		}
+		data := ins.GetData()
+		if len(data) == 0 {
+			return nil, errors.New("compute budget instruction data is null")
+		}
+		switch data[0] {
+		case InstructionSetComputeUnitLimit, InstructionRequestHeapFrame, InstructionSetLoadedAccountsDataSizeLimit:
+			if len(data) != 5 {
+				return nil, fmt.Errorf("compute budget instruction [%d] data length is %d", data[0], len(data))
+			}
+			value := binary.LittleEndian.Uint32(data[1:])
+			switch data[0] {
+			case InstructionSetComputeUnitLimit:
+				params.ComputeUnitLimit = value
+			case InstructionRequestHeapFrame:
+				params.HeapFrameBytes = value
+			default:
+				params.LoadedAccountsDataSizeLimit = value
+			}
+		case InstructionSetComputeUnitPrice:
+			if len(data) != 9 {
+				return nil, fmt.Errorf("compute budget instruction [%d] data length is %d", data[0], len(data))
+			}
+			params.ComputeUnitPrice = binary.LittleEndian.Uint64(data[1:])
+		default:
+			return nil, fmt.Errorf("compute budget instruction [%d] is not supported", data[0])
+		}
+	}
+	return params, nil
+}
+
+/*
+优先费 = ceil(price * limit / 1000000)，单位lamports
+*/
+func PriorityFee(computeUnitPrice uint64, computeUnitLimit uint32) uint64 {
+	hi, lo := bits.Mul64(computeUnitPrice, uint64(computeUnitLimit))
+	lo, carry := bits.Add64(lo, MicroLamportsPerLamport-1, 0)
+	hi += carry
+	if hi >= MicroLamportsPerLamport {
+		// 结果超过u64
+		return ^uint64(0)
+	}
+	fee, _ := bits.Div64(hi, lo, MicroLamportsPerLamport)
+	return fee
+}
+
+/*
+计算交易的总手续费：签名数 * lamportsPerSignature + 优先费
+签名数包括交易的签名以及Ed25519/Secp256k1 precompile指令中需要校验的签名
+未设置compute unit limit时与runtime的默认值一致：builtin程序（包括compute budget自身）的指令按3000，
+其他指令按200000，最多1400000；需要精确的优先费时应设置ComputeUnitLimit
+*/
+func CalculateFee(tx *transaction.Transaction, lamportsPerSignature uint64) (uint64, error) {
+	// 先编译，ComputeBudgetInstructions会合并到tx.Instructions中
+	message, err := tx.CompileMessage()
+	if err != nil {
+		return 0, err
+	}
+	params, err := ParseParams(tx.Instructions)
+	if err != nil {
+		return 0, err
+	}
+	limit := uint64(params.ComputeUnitLimit)
+	if limit == 0 {
+		for _, ins := range message.Instructions {
+			if builtinProgramIds[message.AccountKeys[ins.ProgramIdIndex]] {
+				limit += MaxBuiltinAllocationComputeUnitLimit
+			} else {
+				limit += DefaultInstructionComputeUnitLimit
+			}
+		}
+	}
+	if limit > MaxComputeUnitLimit {
+		limit = MaxComputeUnitLimit
+	}
+	numSignatures := uint64(message.Header.NumRequiredSignatures)
+	for _, ins := range message.Instructions {
+		programId := message.AccountKeys[ins.ProgramIdIndex]
+		if (programId == ed25519ProgramId || programId == secp256k1ProgramId) && len(ins.Data) > 0 {
+			numSignatures += uint64(ins.Data[0])
+		}
+	}
+	signatureFee := numSignatures * lamportsPerSignature
+	return signatureFee + PriorityFee(params.ComputeUnitPrice, uint32(limit)), nil
+}
+
+func newU32Instruction(index uint8, value uint32) (transaction.ITransactionInstruction, error) {
+	data := make([]byte, 5)
+	data[0] = index
+	binary.LittleEndian.PutUint32(data[1:], value)
+	return newComputeBudgetInstruction(data)
+}
+
+func newComputeBudgetInstruction(data []byte) (transaction.ITransactionInstruction, error) {
+	return transaction.NewTransactionInstruction(ProgramId, []*transaction.AccountMeta{}, data)
+}
@@ -0,0 +1,84 @@
+package ed25519program
+
+/*
+func：
+author： flynn
+date: 2020-08-03
+fork: https://github.com/solana-labs/solana-web3.js/src/ed25519-program.ts
+*/
+import (
+	"crypto/ed25519"
+	"encoding/binary"
+	"fmt"
+	"github.com/JFJun/solana-go/account"
+	"github.com/JFJun/solana-go/transaction"
+)
+
+/*
+Ed25519签名校验的precompile程序id
+*/
+var ProgramId = account.MustPublicKeyFromBase58("Ed25519SigVerify111111111111111111111111111")
+
+const (
+	PublicKeySize = ed25519.PublicKeySize
+	SignatureSize = ed25519.SignatureSize
+	// u16 signature_offset | u16 signature_instruction_index | u16 public_key_offset | u16 public_key_instruction_index
+	// u16 message_data_offset | u16 message_data_size | u16 message_instruction_index
+	SignatureOffsetsSize = 14
+	// u8 签名数量 + u8 padding
+	SignatureOffsetsStart = 2
+	// 表示数据在当前指令中
+	CurrentInstructionIndex = 0xffff
+)
+
+type InstructionParams struct {
+	PublicKey account.PublicKey
+	Message   []byte
+	Signature []byte
+}
+
+type InstructionWithAccountParams struct {
+	Account *account.Account
+	Message []byte
+}
+
+/*
+数据布局：header | offsets | public key | signature | message
+所有数据都在本指令中，instruction index固定为CurrentInstructionIndex
+*/
+func NewInstruction(params InstructionParams) (transaction.ITransactionInstruction, error) {
+	if len(params.Signature) != SignatureSize {
+		return nil, fmt.Errorf("ed25519 signature length [%d] is not valid", len(params.Signature))
+	}
+	publicKeyOffset := SignatureOffsetsStart + SignatureOffsetsSize
+	signatureOffset := publicKeyOffset + PublicKeySize
+	messageDataOffset := signatureOffset + SignatureSize
+	if messageDataOffset+len(params.Message) > 0xffff {
+		return nil, fmt.Errorf("ed25519 message length [%d] is too large", len(params.Message))
+	}
+	data := make([]byte, messageDataOffset+len(params.Message))
+	data[0] = 1 // 签名数量
+	offsets := data[SignatureOffsetsStart:publicKeyOffset]
+	binary.LittleEndian.PutUint16(offsets[0:2], uint16(signatureOffset))
+	binary.LittleEndian.PutUint16(offsets[2:4], CurrentInstructionIndex)
+	binary.LittleEndian.PutUint16(offsets[4:6], uint16(publicKeyOffset))
+	binary.LittleEndian.PutUint16(offsets[6:8], CurrentInstructionIndex)
+	binary.LittleEndian.PutUint16(offsets[8:10], uint16(messageDataOffset))
+	binary.LittleEndian.PutUint16(offsets[10:12], uint16(len(params.Message)))
+	binary.LittleEndian.PutUint16(offsets[12:14], CurrentInstructionIndex)
+	copy(data[publicKeyOffset:], params.PublicKey[:])
+	copy(data[signatureOffset:], params.Signature)
+	copy(data[messageDataOffset:], params.Message)
+	return transaction.NewTransactionInstruction(ProgramId, []*transaction.AccountMeta{}, data)
+}
+
+/*
+用账户私钥对message签名后生成校验指令
+*/
+func NewInstructionWithAccount(params InstructionWithAccountParams) (transaction.ITransactionInstruction, error) {
+	return NewInstruction(InstructionParams{
+		PublicKey: params.Account.GetPublicKey(),
+		Message:   params.Message,
+		Signature: params.Account.Sign(params.Message),
+	})
+}
@@ -2,4 +2,8 @@ module github.com/JFJun/solana-go
 
 go 1.13
 
-require github.com/btcsuite/btcutil v1.0.2
+require (
+	github.com/btcsuite/btcd v0.22.1
+	github.com/btcsuite/btcutil v1.0.3-0.20201208143702-a53e38424cce
+	golang.org/x/crypto v0.0.0-20211117183948-ae814b36b871
+)
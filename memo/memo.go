@@ -0,0 +1,137 @@
+package memo
+
+/*
+func：
+author： flynn
+date: 2020-08-03
+fork: https://github.com/solana-labs/solana-program-library/memo/js/src/index.ts
+*/
+import (
+	"errors"
+	"github.com/JFJun/solana-go/account"
+	"github.com/JFJun/solana-go/rpc"
+	"github.com/JFJun/solana-go/transaction"
+	"unicode/utf8"
+)
+
+/*
+memo program v2的id，v2会校验Signers的签名
+*/
+var ProgramId = account.MustPublicKeyFromBase58("MemoSq4gqABAXKb96qnH8TysNcWxMyWCqXgDLGmfcHr")
+
+/*
+memo program v1的id，不支持签名账户
+*/
+var ProgramIdV1 = account.MustPublicKeyFromBase58("Memo1UhkJRfHyvLMcVucJwxXeuD728EqVDDwQDxFMNo")
+
+type MemoParams struct {
+	Memo      string
+	Signers   []account.PublicKey // 需要对memo签名的账户，只读；只有v2支持
+	ProgramId account.PublicKey   // 为空时使用v2
+}
+
+func NewMemo(params MemoParams) (transaction.ITransactionInstruction, error) {
+	programId := params.ProgramId
+	if programId.IsZero() {
+		programId = ProgramId
+	}
+	if programId != ProgramId && programId != ProgramIdV1 {
+		return nil, errors.New("program id is not memo program")
+	}
+	if programId == ProgramIdV1 && len(params.Signers) > 0 {
+		return nil, errors.New("memo program v1 does not support signers")
+	}
+	if params.Memo == "" {
+		return nil, errors.New("memo is null")
+	}
+	if !utf8.ValidString(params.Memo) {
+		return nil, errors.New("memo is not valid utf8")
+	}
+	keys := []*transaction.AccountMeta{}
+	for _, signer := range params.Signers {
+		keys = append(keys, transaction.NewAccountMeta(signer, true, false))
+	}
+	return transaction.NewTransactionInstruction(programId, keys, []byte(params.Memo))
+}
+
+/*
+在交易末尾追加一条memo指令
+*/
+func AddMemo(tx *transaction.Transaction, params MemoParams) error {
+	ins, err := NewMemo(params)
+	if err != nil {
+		return err
+	}
+	tx.SetInstructions(ins)
+	return nil
+}
+
+func IsMemoProgram(programId account.PublicKey) bool {
+	return programId == ProgramId || programId == ProgramIdV1
+}
+
+/*
+按指令顺序提取交易中的memo
+*/
+func ExtractMemos(tx *transaction.Transaction) []string {
+	var memos []string
+	for _, ins := range tx.Instructions {
+		if IsMemoProgram(ins.GetProgramId()) {
+			memos = append(memos, string(ins.GetData()))
+		}
+	}
+	return memos
+}
+
+func ExtractMemosFromMessage(message *transaction.Message) []string {
+	return extractCompiledMemos(message.AccountKeys, message.Instructions)
+}
+
+/*
+program id一定在静态账户中，不需要地址查找表
+*/
+func ExtractMemosFromMessageV0(message *transaction.MessageV0) []string {
+	return extractCompiledMemos(message.StaticAccountKeys, message.Instructions)
+}
+
+/*
+解析wire格式的交易（legacy或v0）并提取memo
+*/
+func ExtractMemosFromWireTransaction(data []byte) ([]string, error) {
+	_, messageData, err := transaction.SplitTransaction(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(messageData) > 0 && messageData[0]&transaction.VersionPrefixMask != 0 {
+		message, err := transaction.DeserializeMessageV0(messageData)
+		if err != nil {
+			return nil, err
+		}
+		return ExtractMemosFromMessageV0(message), nil
+	}
+	message, err := transaction.DeserializeMessage(messageData)
+	if err != nil {
+		return nil, err
+	}
+	return ExtractMemosFromMessage(message), nil
+}
+
+/*
+从rpc GetTransaction的结果中提取memo；失败的交易也会返回memo，入账前需要检查Meta.Err
+*/
+func ExtractMemosFromRpcTransaction(result *rpc.TransactionResult) ([]string, error) {
+	if result == nil {
+		return nil, errors.New("transaction result is nil")
+	}
+	return ExtractMemosFromWireTransaction(result.Transaction)
+}
+
+func extractCompiledMemos(accountKeys []account.PublicKey, instructions []*transaction.CompiledInstruction) []string {
+	var memos []string
+	for _, ins := range instructions {
+		if ins.ProgramIdIndex < len(accountKeys) && IsMemoProgram(accountKeys[ins.ProgramIdIndex]) {
+			memos = append(memos, string(ins.Data))
+		}
+	}
+	return memos
+}
@@ -0,0 +1,275 @@
+package noncepool
+
+/*
+func：nonce账户池，用于离线签名时同时持有多笔未上链的durable nonce交易
+author： flynn
+date: 2020-08-03
+*/
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/JFJun/solana-go/account"
+	"github.com/JFJun/solana-go/rpc"
+	"github.com/JFJun/solana-go/systemprogram"
+	"github.com/JFJun/solana-go/transaction"
+)
+
+type NonceStatus int
+
+const (
+	NonceStale     NonceStatus = iota // nonce值未知或已失效，需要Refresh
+	NonceAvailable                    // 可以分配
+	NonceReserved                     // 已分配给某笔交易，尚未广播
+	NonceConsumed                     // 交易已广播，等待上链后刷新nonce值
+)
+
+func (s NonceStatus) String() string {
+	switch s {
+	case NonceStale:
+		return "stale"
+	case NonceAvailable:
+		return "available"
+	case NonceReserved:
+		return "reserved"
+	case NonceConsumed:
+		return "consumed"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(s))
+	}
+}
+
+var ErrNoAvailableNonce = errors.New("no available nonce account in pool")
+
+type nonceEntry struct {
+	account account.PublicKey
+	value   string
+	status  NonceStatus
+}
+
+/*
+分配给调用方的nonce，NonceInfo可直接设置到Transaction.NonceInfo
+*/
+type Reservation struct {
+	NonceAccount account.PublicKey
+	NonceInfo    *transaction.NonceInformation
+}
+
+type Pool struct {
+	client    *rpc.RpcClient
+	authority account.PublicKey
+
+	mu      sync.Mutex
+	entries map[account.PublicKey]*nonceEntry
+	order   []account.PublicKey // 按加入顺序分配，结果可预期
+}
+
+/*
+authority为池中所有nonce账户的授权账户，由它签名AdvanceNonceAccount
+*/
+func New(client *rpc.RpcClient, authority account.PublicKey) *Pool {
+	return &Pool{
+		client:    client,
+		authority: authority,
+		entries:   make(map[account.PublicKey]*nonceEntry),
+	}
+}
+
+func (p *Pool) Authority() account.PublicKey {
+	return p.authority
+}
+
+/*
+把已存在的nonce账户加入池中，状态为stale，Refresh后才能分配
+*/
+func (p *Pool) Add(nonceAccounts ...account.PublicKey) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, na := range nonceAccounts {
+		if _, ok := p.entries[na]; ok {
+			continue
+		}
+		p.entries[na] = &nonceEntry{account: na, status: NonceStale}
+		p.order = append(p.order, na)
+	}
+}
+
+/*
+生成n个新的nonce账户及创建交易（已由payer和nonce账户签名），调用方广播并确认后再调用Refresh
+*/
+func (p *Pool) Provision(payer *account.Account, n int, recentBlockHash string) ([]*transaction.Transaction, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("provision nonce account number [%d] is not valid", n)
+	}
+	lamports, err := p.client.GetMinimumBalanceForRentExemption(systemprogram.NonceAccountLength)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		txs    []*transaction.Transaction
+		nonces []account.PublicKey
+	)
+	for i := 0; i < n; i++ {
+		nonce, err := account.NewAccount()
+		if err != nil {
+			return nil, err
+		}
+		ins, err := transaction.NewCreateNonceAccount(transaction.CreateNonceAccountParams{
+			From:       payer.GetPublicKey(),
+			Nonce:      nonce.GetPublicKey(),
+			Authorized: p.authority,
+			Lamports:   lamports,
+		})
+		if err != nil {
+			return nil, err
+		}
+		tx := transaction.NewTransaction(recentBlockHash)
+		for _, in := range ins {
+			tx.SetInstructions(in)
+		}
+		if err := tx.Sign([]*account.Account{payer, nonce}); err != nil {
+			return nil, err
+		}
+		txs = append(txs, tx)
+		nonces = append(nonces, nonce.GetPublicKey())
+	}
+	p.Add(nonces...)
+	return txs, nil
+}
+
+/*
+分配一个可用的nonce，并发安全；没有可用nonce时返回ErrNoAvailableNonce
+*/
+func (p *Pool) Reserve() (*Reservation, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, na := range p.order {
+		entry := p.entries[na]
+		if entry.status != NonceAvailable {
+			continue
+		}
+		info, err := transaction.NewNonceInformation(entry.account, p.authority, entry.value)
+		if err != nil {
+			return nil, err
+		}
+		entry.status = NonceReserved
+		return &Reservation{NonceAccount: entry.account, NonceInfo: info}, nil
+	}
+	return nil, ErrNoAvailableNonce
+}
+
+/*
+交易未广播，归还nonce，nonce值仍然有效
+*/
+func (p *Pool) Release(nonceAccount account.PublicKey) error {
+	return p.transition(nonceAccount, NonceReserved, NonceAvailable)
+}
+
+/*
+交易已广播，nonce会在上链后推进，需要Refresh
+*/
+func (p *Pool) MarkConsumed(nonceAccount account.PublicKey) error {
+	return p.transition(nonceAccount, NonceReserved, NonceConsumed)
+}
+
+/*
+nonce值已失效（例如交易因nonce不匹配失败），任何状态都可以标记
+*/
+func (p *Pool) MarkStale(nonceAccount account.PublicKey) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	entry, ok := p.entries[nonceAccount]
+	if !ok {
+		return fmt.Errorf("nonce account [%s] is not in pool", nonceAccount.String())
+	}
+	entry.status = NonceStale
+	return nil
+}
+
+func (p *Pool) transition(nonceAccount account.PublicKey, from, to NonceStatus) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	entry, ok := p.entries[nonceAccount]
+	if !ok {
+		return fmt.Errorf("nonce account [%s] is not in pool", nonceAccount.String())
+	}
+	if entry.status != from {
+		return fmt.Errorf("nonce account [%s] status is %s, not %s", nonceAccount.String(), entry.status, from)
+	}
+	entry.status = to
+	return nil
+}
+
+/*
+通过rpc刷新stale和consumed状态的nonce值
+consumed的nonce值未变化说明交易还未上链，保持consumed；rpc请求期间不持有锁
+*/
+func (p *Pool) Refresh() error {
+	type pending struct {
+		account account.PublicKey
+		value   string
+		status  NonceStatus
+	}
+	var todo []pending
+	p.mu.Lock()
+	for _, na := range p.order {
+		entry := p.entries[na]
+		if entry.status == NonceStale || entry.status == NonceConsumed {
+			todo = append(todo, pending{account: na, value: entry.value, status: entry.status})
+		}
+	}
+	p.mu.Unlock()
+
+	var errs []error
+	for _, item := range todo {
+		na, err := systemprogram.GetNonceAccount(p.client, item.account)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !na.Authority.Equals(p.authority) {
+			errs = append(errs, fmt.Errorf("nonce account [%s] authority is %s, not %s",
+				item.account.String(), na.Authority.String(), p.authority.String()))
+			continue
+		}
+		if item.status == NonceConsumed && na.Nonce == item.value {
+			continue
+		}
+		p.mu.Lock()
+		entry := p.entries[item.account]
+		// 刷新期间状态被其他goroutine修改过，以修改后的状态为准
+		if entry.status == item.status && entry.value == item.value {
+			entry.value = na.Nonce
+			entry.status = NonceAvailable
+		}
+		p.mu.Unlock()
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("refresh %d nonce accounts failed, first error: %v", len(errs), errs[0])
+	}
+	return nil
+}
+
+/*
+返回各状态的nonce数量
+*/
+func (p *Pool) Stats() map[NonceStatus]int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	stats := make(map[NonceStatus]int)
+	for _, entry := range p.entries {
+		stats[entry.status]++
+	}
+	return stats
+}
+
+func (p *Pool) Status(nonceAccount account.PublicKey) (NonceStatus, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	entry, ok := p.entries[nonceAccount]
+	if !ok {
+		return NonceStale, false
+	}
+	return entry.status, true
+}
@@ -33,6 +33,13 @@ type RespBody struct {
 	Id      int         `json:"id"`
 }
 
+type rawRespBody struct {
+	JsonRpc string          `json:"jsonrpc"`
+	Result  json.RawMessage `json:"result"`
+	Error   interface{}     `json:"error"`
+	Id      int             `json:"id"`
+}
+
 //初始化一个rpc客户端
 func New(url, user, password string) *RpcClient {
 	return &RpcClient{
@@ -43,6 +50,35 @@ func New(url, user, password string) *RpcClient {
 }
 
 func (rpc *RpcClient) SendRequest(method string, params []interface{}) ([]byte, error) {
+	raw, err := rpc.call(method, params)
+	if err != nil {
+		return nil, err
+	}
+	var result interface{}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, errors.New(fmt.Sprintf("Parse result error,Err=【%v】", err))
+	}
+
+	//如果返回的结果直接是一个string，就不在做json处理了，直接返回
+	switch result.(type) {
+	case string:
+		return []byte(result.(string)), nil
+	case float64:
+		f := strconv.FormatFloat(result.(float64), 'f', -1, 64)
+		return []byte(f), nil
+	default:
+		data, err := json.Marshal(result)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("Marshal result error,Err=【%v】", err))
+		}
+		return data, nil
+	}
+}
+
+/*
+返回原始的result，避免u64等大数经过float64丢失精度
+*/
+func (rpc *RpcClient) call(method string, params []interface{}) (json.RawMessage, error) {
 	id := rand.Intn(10000)
 	var (
 		reqBytes []byte
@@ -92,27 +128,18 @@ func (rpc *RpcClient) SendRequest(method string, params []interface{}) ([]byte,
 		return nil, err
 	}
 	//解析resp
-	var response RespBody
+	var response rawRespBody
 	if err := json.Unmarshal(resp, &response); err != nil {
 		return nil, errors.New(fmt.Sprintf("Parse resp error,Err=【%v】", err))
 	}
 	if response.Error != nil {
-		rpcErr := response.Error.(map[string]interface{})
-		return nil, errors.New(fmt.Sprintf("Rpc get error,Code=【%d】,Message=【%s】", int(rpcErr["code"].(float64)), rpcErr["message"].(string)))
-	}
-
-	//如果返回的结果直接是一个string，就不在做json处理了，直接返回
-	switch response.Result.(type) {
-	case string:
-		return []byte(response.Result.(string)), nil
-	case float64:
-		f := strconv.FormatFloat(response.Result.(float64), 'f', -1, 64)
-		return []byte(f), nil
-	default:
-		data, err := json.Marshal(response.Result)
-		if err != nil {
-			return nil, errors.New(fmt.Sprintf("Marshal result error,Err=【%v】", err))
+		rpcErr, ok := response.Error.(map[string]interface{})
+		if !ok {
+			return nil, errors.New(fmt.Sprintf("Rpc get error,Err=【%v】", response.Error))
 		}
-		return data, nil
+		code, _ := rpcErr["code"].(float64)
+		message, _ := rpcErr["message"].(string)
+		return nil, errors.New(fmt.Sprintf("Rpc get error,Code=【%d】,Message=【%s】", int(code), message))
 	}
+	return response.Result, nil
 }
@@ -0,0 +1,147 @@
+package rpc
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/JFJun/solana-go/account"
+)
+
+type RpcContext struct {
+	Slot uint64 `json:"slot"`
+}
+
+type AccountInfo struct {
+	Lamports   uint64
+	Owner      account.PublicKey
+	Data       []byte
+	Executable bool
+	RentEpoch  uint64
+}
+
+type rpcAccountInfo struct {
+	Lamports   uint64            `json:"lamports"`
+	Owner      account.PublicKey `json:"owner"`
+	Data       []string          `json:"data"`
+	Executable bool              `json:"executable"`
+	RentEpoch  uint64            `json:"rentEpoch"`
+}
+
+func (rpc *RpcClient) GetBalance(pubkey account.PublicKey) (uint64, error) {
+	raw, err := rpc.call("getBalance", []interface{}{pubkey})
+	if err != nil {
+		return 0, err
+	}
+	var result struct {
+		Context RpcContext `json:"context"`
+		Value   uint64     `json:"value"`
+	}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return 0, fmt.Errorf("parse balance error,Err=%v", err)
+	}
+	return result.Value, nil
+}
+
+/*
+获取账户信息，data按base64解码为原始字节；账户不存在时返回nil
+*/
+func (rpc *RpcClient) GetAccountInfo(pubkey account.PublicKey) (*AccountInfo, error) {
+	raw, err := rpc.call("getAccountInfo", []interface{}{
+		pubkey,
+		map[string]interface{}{"encoding": "base64"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var result struct {
+		Context RpcContext      `json:"context"`
+		Value   *rpcAccountInfo `json:"value"`
+	}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("parse account info error,Err=%v", err)
+	}
+	if result.Value == nil {
+		return nil, nil
+	}
+	if len(result.Value.Data) != 2 || result.Value.Data[1] != "base64" {
+		return nil, errors.New("account data is not base64 encoding")
+	}
+	data, err := base64.StdEncoding.DecodeString(result.Value.Data[0])
+	if err != nil {
+		return nil, fmt.Errorf("decode account data error,Err=%v", err)
+	}
+	return &AccountInfo{
+		Lamports:   result.Value.Lamports,
+		Owner:      result.Value.Owner,
+		Data:       data,
+		Executable: result.Value.Executable,
+		RentEpoch:  result.Value.RentEpoch,
+	}, nil
+}
+
+func (rpc *RpcClient) GetMinimumBalanceForRentExemption(dataLength uint64) (uint64, error) {
+	raw, err := rpc.call("getMinimumBalanceForRentExemption", []interface{}{dataLength})
+	if err != nil {
+		return 0, err
+	}
+	var lamports uint64
+	if err := json.Unmarshal(raw, &lamports); err != nil {
+		return 0, fmt.Errorf("parse minimum balance error,Err=%v", err)
+	}
+	return lamports, nil
+}
+
+type TransactionMeta struct {
+	Err          interface{} `json:"err"` // 为nil表示执行成功
+	Fee          uint64      `json:"fee"`
+	PreBalances  []uint64    `json:"preBalances"`
+	PostBalances []uint64    `json:"postBalances"`
+	LogMessages  []string    `json:"logMessages"`
+}
+
+type TransactionResult struct {
+	Slot        uint64
+	BlockTime   *int64
+	Transaction []byte // wire格式的交易，可用transaction.DeserializeTransaction解析
+	Meta        *TransactionMeta
+}
+
+/*
+按签名查询已确认的交易，交易以base64返回并解码；支持v0交易，交易不存在时返回nil
+*/
+func (rpc *RpcClient) GetTransaction(signature string) (*TransactionResult, error) {
+	raw, err := rpc.call("getTransaction", []interface{}{
+		signature,
+		map[string]interface{}{"encoding": "base64", "maxSupportedTransactionVersion": 0},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var result *struct {
+		Slot        uint64           `json:"slot"`
+		BlockTime   *int64           `json:"blockTime"`
+		Transaction []string         `json:"transaction"`
+		Meta        *TransactionMeta `json:"meta"`
+	}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("parse transaction error,Err=%v", err)
+	}
+	if result == nil {
+		return nil, nil
+	}
+	if len(result.Transaction) != 2 || result.Transaction[1] != "base64" {
+		return nil, errors.New("transaction is not base64 encoding")
+	}
+	data, err := base64.StdEncoding.DecodeString(result.Transaction[0])
+	if err != nil {
+		return nil, fmt.Errorf("decode transaction error,Err=%v", err)
+	}
+	return &TransactionResult{
+		Slot:        result.Slot,
+		BlockTime:   result.BlockTime,
+		Transaction: data,
+		Meta:        result.Meta,
+	}, nil
+}
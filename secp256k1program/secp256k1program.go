@@ -0,0 +1,190 @@
+package secp256k1program
+
+/*
+func：
+author： flynn
+date: 2020-08-03
+fork: https://github.com/solana-labs/solana-web3.js/src/secp256k1-program.ts
+*/
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/JFJun/solana-go/account"
+	"github.com/JFJun/solana-go/transaction"
+	"github.com/btcsuite/btcd/btcec"
+	"golang.org/x/crypto/sha3"
+)
+
+/*
+Secp256k1签名校验（以太坊地址恢复）的precompile程序id
+*/
+var ProgramId = account.MustPublicKeyFromBase58("KeccakSecp256k11111111111111111111111111111")
+
+const (
+	EthAddressSize = 20
+	SignatureSize  = 64 // r | s，之后紧跟1字节recovery id
+	PublicKeySize  = 64 // 去掉0x04前缀的非压缩公钥
+	// u16 signature_offset | u8 signature_instruction_index | u16 eth_address_offset | u8 eth_address_instruction_index
+	// u16 message_data_offset | u16 message_data_size | u8 message_instruction_index
+	SignatureOffsetsSize = 11
+	// u8 签名数量
+	SignatureOffsetsStart = 1
+)
+
+/*
+InstructionIndex是本指令在交易中的位置，程序按该位置读取签名数据
+*/
+type InstructionParams struct {
+	EthAddress       []byte
+	Message          []byte
+	Signature        []byte
+	RecoveryId       uint8
+	InstructionIndex uint8
+}
+
+type InstructionWithPrivateKeyParams struct {
+	PrivateKey       []byte
+	Message          []byte
+	InstructionIndex uint8
+}
+
+/*
+数据布局：header | offsets | eth address | signature | recovery id | message
+*/
+func NewInstruction(params InstructionParams) (transaction.ITransactionInstruction, error) {
+	if len(params.EthAddress) != EthAddressSize {
+		return nil, fmt.Errorf("eth address length [%d] is not valid", len(params.EthAddress))
+	}
+	if len(params.Signature) != SignatureSize {
+		return nil, fmt.Errorf("secp256k1 signature length [%d] is not valid", len(params.Signature))
+	}
+	if params.RecoveryId > 3 {
+		return nil, fmt.Errorf("secp256k1 recovery id [%d] is not valid", params.RecoveryId)
+	}
+	ethAddressOffset := SignatureOffsetsStart + SignatureOffsetsSize
+	signatureOffset := ethAddressOffset + EthAddressSize
+	messageDataOffset := signatureOffset + SignatureSize + 1
+	if messageDataOffset+len(params.Message) > 0xffff {
+		return nil, fmt.Errorf("secp256k1 message length [%d] is too large", len(params.Message))
+	}
+	data := make([]byte, messageDataOffset+len(params.Message))
+	data[0] = 1 // 签名数量
+	offsets := data[SignatureOffsetsStart:ethAddressOffset]
+	binary.LittleEndian.PutUint16(offsets[0:2], uint16(signatureOffset))
+	offsets[2] = params.InstructionIndex
+	binary.LittleEndian.PutUint16(offsets[3:5], uint16(ethAddressOffset))
+	offsets[5] = params.InstructionIndex
+	binary.LittleEndian.PutUint16(offsets[6:8], uint16(messageDataOffset))
+	binary.LittleEndian.PutUint16(offsets[8:10], uint16(len(params.Message)))
+	offsets[10] = params.InstructionIndex
+	copy(data[ethAddressOffset:], params.EthAddress)
+	copy(data[signatureOffset:], params.Signature)
+	data[signatureOffset+SignatureSize] = params.RecoveryId
+	copy(data[messageDataOffset:], params.Message)
+	return transaction.NewTransactionInstruction(ProgramId, []*transaction.AccountMeta{}, data)
+}
+
+/*
+用secp256k1私钥对keccak256(message)签名后生成校验指令
+*/
+func NewInstructionWithPrivateKey(params InstructionWithPrivateKeyParams) (transaction.ITransactionInstruction, error) {
+	signature, recoveryId, err := Sign(params.PrivateKey, params.Message)
+	if err != nil {
+		return nil, err
+	}
+	privateKey, _ := btcec.PrivKeyFromBytes(btcec.S256(), params.PrivateKey)
+	ethAddress, err := PublicKeyToEthAddress(privateKey.PubKey().SerializeUncompressed())
+	if err != nil {
+		return nil, err
+	}
+	return NewInstruction(InstructionParams{
+		EthAddress:       ethAddress,
+		Message:          params.Message,
+		Signature:        signature,
+		RecoveryId:       recoveryId,
+		InstructionIndex: params.InstructionIndex,
+	})
+}
+
+/*
+以太坊地址：keccak256(非压缩公钥去掉0x04前缀)的后20字节
+publicKey可以是64字节、65字节非压缩或33字节压缩格式
+*/
+func PublicKeyToEthAddress(publicKey []byte) ([]byte, error) {
+	if len(publicKey) == PublicKeySize {
+		publicKey = append([]byte{0x04}, publicKey...)
+	}
+	pub, err := btcec.ParsePubKey(publicKey, btcec.S256())
+	if err != nil {
+		return nil, fmt.Errorf("parse secp256k1 public key error,Err=%v", err)
+	}
+	hash := Keccak256(pub.SerializeUncompressed()[1:])
+	return hash[len(hash)-EthAddressSize:], nil
+}
+
+/*
+签名格式与precompile一致：64字节r|s和recovery id
+*/
+func Sign(privateKey, message []byte) ([]byte, uint8, error) {
+	if len(privateKey) != 32 {
+		return nil, 0, fmt.Errorf("secp256k1 private key length [%d] is not valid", len(privateKey))
+	}
+	key, _ := btcec.PrivKeyFromBytes(btcec.S256(), privateKey)
+	compact, err := btcec.SignCompact(btcec.S256(), key, Keccak256(message), false)
+	if err != nil {
+		return nil, 0, fmt.Errorf("secp256k1 sign error,Err=%v", err)
+	}
+	// compact签名：27 + recovery id | r | s
+	return compact[1:], compact[0] - 27, nil
+}
+
+/*
+从签名中恢复签名者的非压缩公钥（64字节，不含0x04前缀）
+*/
+func RecoverPublicKey(message, signature []byte, recoveryId uint8) ([]byte, error) {
+	if len(signature) != SignatureSize {
+		return nil, fmt.Errorf("secp256k1 signature length [%d] is not valid", len(signature))
+	}
+	if recoveryId > 3 {
+		return nil, fmt.Errorf("secp256k1 recovery id [%d] is not valid", recoveryId)
+	}
+	compact := append([]byte{27 + recoveryId}, signature...)
+	pub, _, err := btcec.RecoverCompact(btcec.S256(), compact, Keccak256(message))
+	if err != nil {
+		return nil, fmt.Errorf("recover secp256k1 public key error,Err=%v", err)
+	}
+	return pub.SerializeUncompressed()[1:], nil
+}
+
+/*
+从签名中恢复签名者的以太坊地址
+*/
+func RecoverEthAddress(message, signature []byte, recoveryId uint8) ([]byte, error) {
+	publicKey, err := RecoverPublicKey(message, signature, recoveryId)
+	if err != nil {
+		return nil, err
+	}
+	return PublicKeyToEthAddress(publicKey)
+}
+
+/*
+解析"0x"开头的16进制以太坊地址
+*/
+func ParseEthAddress(address string) ([]byte, error) {
+	if len(address) != 2+EthAddressSize*2 || (address[:2] != "0x" && address[:2] != "0X") {
+		return nil, errors.New("eth address is not valid")
+	}
+	data, err := hex.DecodeString(address[2:])
+	if err != nil {
+		return nil, fmt.Errorf("eth address is not valid,Err=%v", err)
+	}
+	return data, nil
+}
+
+func Keccak256(data []byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	h.Write(data)
+	return h.Sum(nil)
+}
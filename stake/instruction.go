@@ -0,0 +1,296 @@
+package stake
+
+/*
+func：
+author： flynn
+date: 2020-08-03
+fork: https://github.com/solana-labs/solana-web3.js/src/programs/stake.ts
+*/
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"github.com/JFJun/solana-go/account"
+	"github.com/JFJun/solana-go/sysvar"
+	"github.com/JFJun/solana-go/transaction"
+)
+
+type InitializeParams struct {
+	StakePubkey account.PublicKey
+	Authorized  Authorized
+	Lockup      Lockup
+}
+
+/*
+与Initialize相同，但withdrawer需要签名，没有lockup
+*/
+type InitializeCheckedParams struct {
+	StakePubkey account.PublicKey
+	Authorized  Authorized
+}
+
+type CreateStakeAccountParams struct {
+	From        account.PublicKey
+	StakePubkey account.PublicKey
+	Authorized  Authorized
+	Lockup      Lockup
+	Lamports    uint64 // 需要包含StakeAccountLength的免租金额
+}
+
+type AuthorizeParams struct {
+	StakePubkey      account.PublicKey
+	AuthorizedPubkey account.PublicKey
+	NewAuthorized    account.PublicKey
+	StakeAuthorize   StakeAuthorize
+	Custodian        *account.PublicKey // 锁定期内修改withdrawer时需要
+}
+
+type DelegateStakeParams struct {
+	StakePubkey      account.PublicKey
+	AuthorizedPubkey account.PublicKey
+	VotePubkey       account.PublicKey
+}
+
+type SplitParams struct {
+	StakePubkey      account.PublicKey
+	AuthorizedPubkey account.PublicKey
+	SplitStakePubkey account.PublicKey // 需要预先分配StakeAccountLength空间并由stake program拥有
+	Lamports         uint64
+}
+
+type MergeParams struct {
+	StakePubkey       account.PublicKey // 合并的目标
+	SourceStakePubkey account.PublicKey
+	AuthorizedPubkey  account.PublicKey
+}
+
+type WithdrawParams struct {
+	StakePubkey      account.PublicKey
+	AuthorizedPubkey account.PublicKey
+	ToPubkey         account.PublicKey
+	Lamports         uint64
+	Custodian        *account.PublicKey
+}
+
+type DeactivateParams struct {
+	StakePubkey      account.PublicKey
+	AuthorizedPubkey account.PublicKey
+}
+
+/*
+为nil的字段保持不变；AuthorizedPubkey在锁定期内为custodian，否则为withdrawer
+*/
+type SetLockupParams struct {
+	StakePubkey      account.PublicKey
+	AuthorizedPubkey account.PublicKey
+	UnixTimestamp    *int64
+	Epoch            *uint64
+	Custodian        *account.PublicKey
+}
+
+type RedelegateParams struct {
+	StakePubkey      account.PublicKey
+	AuthorizedPubkey account.PublicKey
+	NewStakePubkey   account.PublicKey // 未初始化的stake账户
+	NewVotePubkey    account.PublicKey
+}
+
+func NewInitialize(params InitializeParams) (transaction.ITransactionInstruction, error) {
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, InstructionInitialize)
+	buf.Write(params.Authorized.Staker[:])
+	buf.Write(params.Authorized.Withdrawer[:])
+	binary.Write(buf, binary.LittleEndian, params.Lockup.UnixTimestamp)
+	binary.Write(buf, binary.LittleEndian, params.Lockup.Epoch)
+	buf.Write(params.Lockup.Custodian[:])
+	return newStakeInstruction([]*transaction.AccountMeta{
+		transaction.NewAccountMeta(params.StakePubkey, false, true),
+		transaction.NewAccountMeta(sysvar.RentPubkey, false, false),
+	}, buf.Bytes())
+}
+
+func NewInitializeChecked(params InitializeCheckedParams) (transaction.ITransactionInstruction, error) {
+	return newStakeInstruction([]*transaction.AccountMeta{
+		transaction.NewAccountMeta(params.StakePubkey, false, true),
+		transaction.NewAccountMeta(sysvar.RentPubkey, false, false),
+		transaction.NewAccountMeta(params.Authorized.Staker, false, false),
+		transaction.NewAccountMeta(params.Authorized.Withdrawer, true, false),
+	}, encodeIndex(InstructionInitializeChecked))
+}
+
+/*
+CreateAccount(space=StakeAccountLength, owner=stake program) + Initialize，需要From和StakePubkey签名
+*/
+func NewCreateStakeAccount(params CreateStakeAccountParams) ([]transaction.ITransactionInstruction, error) {
+	create, err := transaction.NewCreateAccount(transaction.CreateAccountParams{
+		From:       params.From,
+		NewAccount: params.StakePubkey,
+		Lamports:   params.Lamports,
+		Space:      StakeAccountLength,
+		ProgramId:  ProgramId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	initialize, err := NewInitialize(InitializeParams{
+		StakePubkey: params.StakePubkey,
+		Authorized:  params.Authorized,
+		Lockup:      params.Lockup,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return []transaction.ITransactionInstruction{create, initialize}, nil
+}
+
+func NewAuthorize(params AuthorizeParams) (transaction.ITransactionInstruction, error) {
+	if params.StakeAuthorize > StakeAuthorizeWithdrawer {
+		return nil, fmt.Errorf("stake authorize [%d] is not valid", params.StakeAuthorize)
+	}
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, InstructionAuthorize)
+	buf.Write(params.NewAuthorized[:])
+	binary.Write(buf, binary.LittleEndian, params.StakeAuthorize)
+	keys := []*transaction.AccountMeta{
+		transaction.NewAccountMeta(params.StakePubkey, false, true),
+		transaction.NewAccountMeta(sysvar.ClockPubkey, false, false),
+		transaction.NewAccountMeta(params.AuthorizedPubkey, true, false),
+	}
+	if params.Custodian != nil {
+		keys = append(keys, transaction.NewAccountMeta(*params.Custodian, true, false))
+	}
+	return newStakeInstruction(keys, buf.Bytes())
+}
+
+/*
+与Authorize相同，但新的authority也需要签名
+*/
+func NewAuthorizeChecked(params AuthorizeParams) (transaction.ITransactionInstruction, error) {
+	if params.StakeAuthorize > StakeAuthorizeWithdrawer {
+		return nil, fmt.Errorf("stake authorize [%d] is not valid", params.StakeAuthorize)
+	}
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, InstructionAuthorizeChecked)
+	binary.Write(buf, binary.LittleEndian, params.StakeAuthorize)
+	keys := []*transaction.AccountMeta{
+		transaction.NewAccountMeta(params.StakePubkey, false, true),
+		transaction.NewAccountMeta(sysvar.ClockPubkey, false, false),
+		transaction.NewAccountMeta(params.AuthorizedPubkey, true, false),
+		transaction.NewAccountMeta(params.NewAuthorized, true, false),
+	}
+	if params.Custodian != nil {
+		keys = append(keys, transaction.NewAccountMeta(*params.Custodian, true, false))
+	}
+	return newStakeInstruction(keys, buf.Bytes())
+}
+
+func NewDelegateStake(params DelegateStakeParams) (transaction.ITransactionInstruction, error) {
+	return newStakeInstruction([]*transaction.AccountMeta{
+		transaction.NewAccountMeta(params.StakePubkey, false, true),
+		transaction.NewAccountMeta(params.VotePubkey, false, false),
+		transaction.NewAccountMeta(sysvar.ClockPubkey, false, false),
+		transaction.NewAccountMeta(sysvar.StakeHistoryPubkey, false, false),
+		transaction.NewAccountMeta(ConfigId, false, false),
+		transaction.NewAccountMeta(params.AuthorizedPubkey, true, false),
+	}, encodeIndex(InstructionDelegateStake))
+}
+
+func NewSplit(params SplitParams) (transaction.ITransactionInstruction, error) {
+	return newStakeInstruction([]*transaction.AccountMeta{
+		transaction.NewAccountMeta(params.StakePubkey, false, true),
+		transaction.NewAccountMeta(params.SplitStakePubkey, false, true),
+		transaction.NewAccountMeta(params.AuthorizedPubkey, true, false),
+	}, encodeIndexU64(InstructionSplit, params.Lamports))
+}
+
+func NewMerge(params MergeParams) (transaction.ITransactionInstruction, error) {
+	return newStakeInstruction([]*transaction.AccountMeta{
+		transaction.NewAccountMeta(params.StakePubkey, false, true),
+		transaction.NewAccountMeta(params.SourceStakePubkey, false, true),
+		transaction.NewAccountMeta(sysvar.ClockPubkey, false, false),
+		transaction.NewAccountMeta(sysvar.StakeHistoryPubkey, false, false),
+		transaction.NewAccountMeta(params.AuthorizedPubkey, true, false),
+	}, encodeIndex(InstructionMerge))
+}
+
+func NewWithdraw(params WithdrawParams) (transaction.ITransactionInstruction, error) {
+	keys := []*transaction.AccountMeta{
+		transaction.NewAccountMeta(params.StakePubkey, false, true),
+		transaction.NewAccountMeta(params.ToPubkey, false, true),
+		transaction.NewAccountMeta(sysvar.ClockPubkey, false, false),
+		transaction.NewAccountMeta(sysvar.StakeHistoryPubkey, false, false),
+		transaction.NewAccountMeta(params.AuthorizedPubkey, true, false),
+	}
+	if params.Custodian != nil {
+		keys = append(keys, transaction.NewAccountMeta(*params.Custodian, true, false))
+	}
+	return newStakeInstruction(keys, encodeIndexU64(InstructionWithdraw, params.Lamports))
+}
+
+func NewDeactivate(params DeactivateParams) (transaction.ITransactionInstruction, error) {
+	return newStakeInstruction([]*transaction.AccountMeta{
+		transaction.NewAccountMeta(params.StakePubkey, false, true),
+		transaction.NewAccountMeta(sysvar.ClockPubkey, false, false),
+		transaction.NewAccountMeta(params.AuthorizedPubkey, true, false),
+	}, encodeIndex(InstructionDeactivate))
+}
+
+/*
+LockupArgs为bincode编码：每个字段为Option，u8 tag + 值
+*/
+func NewSetLockup(params SetLockupParams) (transaction.ITransactionInstruction, error) {
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, InstructionSetLockup)
+	if params.UnixTimestamp != nil {
+		buf.WriteByte(1)
+		binary.Write(buf, binary.LittleEndian, *params.UnixTimestamp)
+	} else {
+		buf.WriteByte(0)
+	}
+	if params.Epoch != nil {
+		buf.WriteByte(1)
+		binary.Write(buf, binary.LittleEndian, *params.Epoch)
+	} else {
+		buf.WriteByte(0)
+	}
+	if params.Custodian != nil {
+		buf.WriteByte(1)
+		buf.Write(params.Custodian[:])
+	} else {
+		buf.WriteByte(0)
+	}
+	return newStakeInstruction([]*transaction.AccountMeta{
+		transaction.NewAccountMeta(params.StakePubkey, false, true),
+		transaction.NewAccountMeta(params.AuthorizedPubkey, true, false),
+	}, buf.Bytes())
+}
+
+/*
+把已激活的stake转到新的vote账户，NewStakePubkey需要预先分配空间并由stake program拥有
+*/
+func NewRedelegate(params RedelegateParams) (transaction.ITransactionInstruction, error) {
+	return newStakeInstruction([]*transaction.AccountMeta{
+		transaction.NewAccountMeta(params.StakePubkey, false, true),
+		transaction.NewAccountMeta(params.NewStakePubkey, false, true),
+		transaction.NewAccountMeta(params.NewVotePubkey, false, false),
+		transaction.NewAccountMeta(ConfigId, false, false),
+		transaction.NewAccountMeta(params.AuthorizedPubkey, true, false),
+	}, encodeIndex(InstructionRedelegate))
+}
+
+func encodeIndex(index uint32) []byte {
+	data := make([]byte, 4)
+	binary.LittleEndian.PutUint32(data, index)
+	return data
+}
+
+func encodeIndexU64(index uint32, value uint64) []byte {
+	data := make([]byte, 12)
+	binary.LittleEndian.PutUint32(data[0:4], index)
+	binary.LittleEndian.PutUint64(data[4:12], value)
+	return data
+}
+
+func newStakeInstruction(keys []*transaction.AccountMeta, data []byte) (transaction.ITransactionInstruction, error) {
+	return transaction.NewTransactionInstruction(ProgramId, keys, data)
+}
@@ -0,0 +1,67 @@
+package stake
+
+/*
+func：
+author： flynn
+date: 2020-08-03
+fork: https://github.com/solana-labs/solana-web3.js/src/programs/stake.ts
+*/
+import "github.com/JFJun/solana-go/account"
+
+/*
+stake program的id
+*/
+var ProgramId = account.MustPublicKeyFromBase58("Stake11111111111111111111111111111111111111")
+
+/*
+stake config账户，DelegateStake和Redelegate需要
+*/
+var ConfigId = account.MustPublicKeyFromBase58("StakeConfig11111111111111111111111111111111")
+
+/*
+stake program指令序号，与solana_program::stake::instruction::StakeInstruction的顺序一致
+*/
+const (
+	InstructionInitialize uint32 = iota
+	InstructionAuthorize
+	InstructionDelegateStake
+	InstructionSplit
+	InstructionWithdraw
+	InstructionDeactivate
+	InstructionSetLockup
+	InstructionMerge
+	InstructionAuthorizeWithSeed
+	InstructionInitializeChecked
+	InstructionAuthorizeChecked
+	InstructionAuthorizeCheckedWithSeed
+	InstructionSetLockupChecked
+	InstructionGetMinimumDelegation
+	InstructionDeactivateDelinquent
+	InstructionRedelegate
+)
+
+type StakeAuthorize uint32
+
+const (
+	StakeAuthorizeStaker StakeAuthorize = iota
+	StakeAuthorizeWithdrawer
+)
+
+/*
+stake账户数据长度
+*/
+const StakeAccountLength = 200
+
+type Authorized struct {
+	Staker     account.PublicKey
+	Withdrawer account.PublicKey
+}
+
+/*
+锁定期内只有custodian可以提取或修改lockup；UnixTimestamp和Epoch都为0表示没有锁定
+*/
+type Lockup struct {
+	UnixTimestamp int64
+	Epoch         uint64
+	Custodian     account.PublicKey
+}
@@ -0,0 +1,122 @@
+package stake
+
+/*
+func：
+author： flynn
+date: 2020-08-03
+fork: https://github.com/solana-labs/solana-web3.js/src/programs/stake.ts
+*/
+import (
+	"encoding/binary"
+	"fmt"
+	"github.com/JFJun/solana-go/account"
+	"github.com/JFJun/solana-go/rpc"
+	"math"
+)
+
+type StakeStateType uint32
+
+const (
+	StakeStateUninitialized StakeStateType = iota
+	StakeStateInitialized
+	StakeStateStake
+	StakeStateRewardsPool
+)
+
+/*
+未停用的stake，DeactivationEpoch为u64最大值
+*/
+const ActiveDeactivationEpoch = ^uint64(0)
+
+type Meta struct {
+	RentExemptReserve uint64
+	Authorized        Authorized
+	Lockup            Lockup
+}
+
+type Delegation struct {
+	VoterPubkey        account.PublicKey
+	Stake              uint64
+	ActivationEpoch    uint64
+	DeactivationEpoch  uint64
+	WarmupCooldownRate float64 // 已废弃
+}
+
+type Stake struct {
+	Delegation      Delegation
+	CreditsObserved uint64
+}
+
+/*
+Meta在Initialized和Stake状态下有值，Stake只在Stake状态下有值
+*/
+type StakeAccount struct {
+	Type       StakeStateType
+	Meta       *Meta
+	Stake      *Stake
+	StakeFlags uint8
+}
+
+func (sa *StakeAccount) IsDelegated() bool {
+	return sa.Type == StakeStateStake && sa.Stake != nil
+}
+
+func (sa *StakeAccount) IsDeactivating() bool {
+	return sa.IsDelegated() && sa.Stake.Delegation.DeactivationEpoch != ActiveDeactivationEpoch
+}
+
+/*
+stake账户数据（bincode）：u32 type | meta(120) | delegation(64) | u64 credits observed | u8 flags
+meta：u64 rent exempt reserve | staker | withdrawer | i64 unix timestamp | u64 epoch | custodian
+delegation：voter | u64 stake | u64 activation epoch | u64 deactivation epoch | f64 warmup cooldown rate
+*/
+func DeserializeStakeAccount(data []byte) (*StakeAccount, error) {
+	if len(data) != StakeAccountLength {
+		return nil, fmt.Errorf("stake account data length is %d, not equal %d", len(data), StakeAccountLength)
+	}
+	sa := &StakeAccount{Type: StakeStateType(binary.LittleEndian.Uint32(data[0:4]))}
+	switch sa.Type {
+	case StakeStateUninitialized, StakeStateRewardsPool:
+		return sa, nil
+	case StakeStateInitialized, StakeStateStake:
+	default:
+		return nil, fmt.Errorf("stake state type [%d] is not valid", sa.Type)
+	}
+	meta := &Meta{RentExemptReserve: binary.LittleEndian.Uint64(data[4:12])}
+	copy(meta.Authorized.Staker[:], data[12:44])
+	copy(meta.Authorized.Withdrawer[:], data[44:76])
+	meta.Lockup.UnixTimestamp = int64(binary.LittleEndian.Uint64(data[76:84]))
+	meta.Lockup.Epoch = binary.LittleEndian.Uint64(data[84:92])
+	copy(meta.Lockup.Custodian[:], data[92:124])
+	sa.Meta = meta
+	if sa.Type == StakeStateInitialized {
+		return sa, nil
+	}
+	stake := &Stake{
+		Delegation: Delegation{
+			Stake:              binary.LittleEndian.Uint64(data[156:164]),
+			ActivationEpoch:    binary.LittleEndian.Uint64(data[164:172]),
+			DeactivationEpoch:  binary.LittleEndian.Uint64(data[172:180]),
+			WarmupCooldownRate: math.Float64frombits(binary.LittleEndian.Uint64(data[180:188])),
+		},
+		CreditsObserved: binary.LittleEndian.Uint64(data[188:196]),
+	}
+	copy(stake.Delegation.VoterPubkey[:], data[124:156])
+	sa.Stake = stake
+	sa.StakeFlags = data[196]
+	return sa, nil
+}
+
+func GetStakeAccount(client *rpc.RpcClient, stakePubkey account.PublicKey) (*StakeAccount, error) {
+	info, err := client.GetAccountInfo(stakePubkey)
+	if err != nil {
+		return nil, err
+	}
+	if info == nil {
+		return nil, fmt.Errorf("stake account [%s] is not found", stakePubkey.String())
+	}
+	if info.Owner != ProgramId {
+		return nil, fmt.Errorf("account [%s] is not owned by stake program", stakePubkey.String())
+	}
+	return DeserializeStakeAccount(info.Data)
+}
@@ -0,0 +1,78 @@
+package systemprogram
+
+/*
+func：
+author： flynn
+date: 2020-08-03
+fork: https://github.com/solana-labs/solana-web3.js/src/nonce-account.js
+*/
+import (
+	"encoding/binary"
+	"fmt"
+	"github.com/JFJun/solana-go/account"
+	"github.com/JFJun/solana-go/rpc"
+	"github.com/btcsuite/btcutil/base58"
+)
+
+const (
+	NonceVersionLegacy uint32 = iota
+	NonceVersionCurrent
+)
+
+const (
+	NonceStateUninitialized uint32 = iota
+	NonceStateInitialized
+)
+
+/*
+nonce账户数据：u32 version | u32 state | authority | nonce(blockhash) | u64 lamports per signature
+*/
+type NonceAccount struct {
+	Version              uint32
+	State                uint32
+	Authority            account.PublicKey
+	Nonce                string // 当前存储的durable blockhash
+	LamportsPerSignature uint64
+}
+
+func (na *NonceAccount) IsInitialized() bool {
+	return na.State == NonceStateInitialized
+}
+
+func DeserializeNonceAccount(data []byte) (*NonceAccount, error) {
+	if len(data) != NonceAccountLength {
+		return nil, fmt.Errorf("nonce account data length is %d, not equal %d", len(data), NonceAccountLength)
+	}
+	na := &NonceAccount{
+		Version:              binary.LittleEndian.Uint32(data[0:4]),
+		State:                binary.LittleEndian.Uint32(data[4:8]),
+		Nonce:                base58.Encode(data[40:72]),
+		LamportsPerSignature: binary.LittleEndian.Uint64(data[72:80]),
+	}
+	copy(na.Authority[:], data[8:40])
+	return na, nil
+}
+
+/*
+通过rpc读取nonce账户，账户必须已初始化
+*/
+func GetNonceAccount(client *rpc.RpcClient, nonce account.PublicKey) (*NonceAccount, error) {
+	info, err := client.GetAccountInfo(nonce)
+	if err != nil {
+		return nil, err
+	}
+	if info == nil {
+		return nil, fmt.Errorf("nonce account [%s] is not found", nonce.String())
+	}
+	if info.Owner != ProgramId {
+		return nil, fmt.Errorf("account [%s] is not owned by system program", nonce.String())
+	}
+	na, err := DeserializeNonceAccount(info.Data)
+	if err != nil {
+		return nil, err
+	}
+	if !na.IsInitialized() {
+		return nil, fmt.Errorf("nonce account [%s] is not initialized", nonce.String())
+	}
+	return na, nil
+}
@@ -0,0 +1,38 @@
+package systemprogram
+
+/*
+func：
+author： flynn
+date: 2020-08-03
+fork: https://github.com/solana-labs/solana-web3.js/src/system-program.js
+*/
+import "github.com/JFJun/solana-go/account"
+
+/*
+system program的id
+*/
+var ProgramId = account.MustPublicKeyFromBase58("11111111111111111111111111111111")
+
+/*
+system program指令序号，与solana_program::system_instruction::SystemInstruction的顺序一致
+*/
+const (
+	InstructionCreateAccount uint32 = iota
+	InstructionAssign
+	InstructionTransfer
+	InstructionCreateAccountWithSeed
+	InstructionAdvanceNonceAccount
+	InstructionWithdrawNonceAccount
+	InstructionInitializeNonceAccount
+	InstructionAuthorizeNonceAccount
+	InstructionAllocate
+	InstructionAllocateWithSeed
+	InstructionAssignWithSeed
+	InstructionTransferWithSeed
+	InstructionUpgradeNonceAccount
+)
+
+/*
+nonce账户数据长度
+*/
+const NonceAccountLength = 80
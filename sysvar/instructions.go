@@ -0,0 +1,89 @@
+package sysvar
+
+/*
+func：
+author： flynn
+date: 2020-08-03
+fork: https://github.com/solana-labs/solana/sdk/program/src/sysvar/instructions.rs
+*/
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/JFJun/solana-go/account"
+)
+
+/*
+instructions sysvar中账户标记位
+*/
+const (
+	instructionAccountSigner   = 1 << 0
+	instructionAccountWritable = 1 << 1
+)
+
+type InstructionAccount struct {
+	PubKey      account.PublicKey
+	IsSigner    bool
+	IsWriteable bool
+}
+
+type Instruction struct {
+	ProgramId account.PublicKey
+	Accounts  []InstructionAccount
+	Data      []byte
+}
+
+/*
+instructions sysvar只在交易执行时由runtime生成，用于程序内省（例如检查前面的ed25519/secp256k1校验指令）
+数据：u16 指令数量 | u16 偏移... | 指令... | u16 当前指令序号
+指令：u16 账户数量 | (u8 标记 | pubkey)... | program id | u16 数据长度 | 数据
+*/
+func DeserializeInstructions(data []byte) ([]Instruction, uint16, error) {
+	if len(data) < 4 {
+		return nil, 0, errors.New("instructions sysvar data is too short")
+	}
+	count := int(binary.LittleEndian.Uint16(data[0:2]))
+	if len(data) < 2+count*2+2 {
+		return nil, 0, fmt.Errorf("instructions sysvar count [%d] is not valid", count)
+	}
+	currentIndex := binary.LittleEndian.Uint16(data[len(data)-2:])
+	body := data[:len(data)-2]
+	instructions := make([]Instruction, count)
+	for i := range instructions {
+		offset := int(binary.LittleEndian.Uint16(data[2+i*2 : 4+i*2]))
+		ins, err := readInstruction(body, offset)
+		if err != nil {
+			return nil, 0, fmt.Errorf("instruction [%d] error,Err=%v", i, err)
+		}
+		instructions[i] = *ins
+	}
+	return instructions, currentIndex, nil
+}
+
+func readInstruction(data []byte, offset int) (*Instruction, error) {
+	if offset+2 > len(data) {
+		return nil, errors.New("instruction offset is out of range")
+	}
+	numAccounts := int(binary.LittleEndian.Uint16(data[offset : offset+2]))
+	offset += 2
+	if offset+numAccounts*33+32+2 > len(data) {
+		return nil, errors.New("instruction accounts are out of range")
+	}
+	ins := &Instruction{Accounts: make([]InstructionAccount, numAccounts)}
+	for i := range ins.Accounts {
+		flags := data[offset]
+		ins.Accounts[i].IsSigner = flags&instructionAccountSigner != 0
+		ins.Accounts[i].IsWriteable = flags&instructionAccountWritable != 0
+		copy(ins.Accounts[i].PubKey[:], data[offset+1:offset+33])
+		offset += 33
+	}
+	copy(ins.ProgramId[:], data[offset:offset+32])
+	offset += 32
+	dataLen := int(binary.LittleEndian.Uint16(data[offset : offset+2]))
+	offset += 2
+	if offset+dataLen > len(data) {
+		return nil, errors.New("instruction data is out of range")
+	}
+	ins.Data = append([]byte{}, data[offset:offset+dataLen]...)
+	return ins, nil
+}
@@ -0,0 +1,395 @@
+package sysvar
+
+/*
+func：
+author： flynn
+date: 2020-08-03
+fork: https://github.com/solana-labs/solana/sdk/program/src/sysvar
+*/
+import (
+	"encoding/binary"
+	"fmt"
+	"github.com/JFJun/solana-go/account"
+	"github.com/JFJun/solana-go/rpc"
+	"github.com/btcsuite/btcutil/base58"
+	"math"
+	"math/big"
+	"math/bits"
+)
+
+const (
+	ClockSize           = 40
+	RentSize            = 17
+	EpochScheduleSize   = 33
+	FeesSize            = 8
+	EpochRewardsSize    = 81
+	LastRestartSlotSize = 8
+
+	// 计算免租金额时每个账户额外计入的存储开销
+	AccountStorageOverhead = 128
+	// 预热阶段第一个epoch的slot数
+	MinimumSlotsPerEpoch = 32
+)
+
+type Clock struct {
+	Slot                uint64
+	EpochStartTimestamp int64
+	Epoch               uint64
+	LeaderScheduleEpoch uint64
+	UnixTimestamp       int64
+}
+
+type Rent struct {
+	LamportsPerByteYear uint64
+	ExemptionThreshold  float64 // 需要预存的年数
+	BurnPercent         uint8
+}
+
+/*
+账户数据长度为dataLen时免租所需的最少lamports
+*/
+func (r *Rent) MinimumBalance(dataLen uint64) uint64 {
+	bytes := float64(AccountStorageOverhead + dataLen)
+	return uint64(bytes * float64(r.LamportsPerByteYear) * r.ExemptionThreshold)
+}
+
+type EpochSchedule struct {
+	SlotsPerEpoch            uint64
+	LeaderScheduleSlotOffset uint64
+	Warmup                   bool
+	FirstNormalEpoch         uint64
+	FirstNormalSlot          uint64
+}
+
+/*
+预热阶段每个epoch的slot数从MinimumSlotsPerEpoch开始逐个翻倍
+*/
+func (es *EpochSchedule) GetSlotsInEpoch(epoch uint64) uint64 {
+	if epoch < es.FirstNormalEpoch {
+		return 1 << (epoch + uint64(bits.TrailingZeros64(MinimumSlotsPerEpoch)))
+	}
+	return es.SlotsPerEpoch
+}
+
+/*
+返回slot所在的epoch以及在该epoch内的序号
+*/
+func (es *EpochSchedule) GetEpochAndSlotIndex(slot uint64) (uint64, uint64) {
+	if slot < es.FirstNormalSlot {
+		// next_power_of_two(slot + MinimumSlotsPerEpoch + 1)
+		nextPowerOfTwo := uint64(1) << uint64(bits.Len64(slot+MinimumSlotsPerEpoch))
+		epoch := uint64(bits.TrailingZeros64(nextPowerOfTwo)) - uint64(bits.TrailingZeros64(MinimumSlotsPerEpoch)) - 1
+		epochLen := es.GetSlotsInEpoch(epoch)
+		return epoch, slot - (epochLen - MinimumSlotsPerEpoch)
+	}
+	normalSlotIndex := slot - es.FirstNormalSlot
+	return es.FirstNormalEpoch + normalSlotIndex/es.SlotsPerEpoch, normalSlotIndex % es.SlotsPerEpoch
+}
+
+/*
+已废弃的sysvar，只包含fee calculator
+*/
+type Fees struct {
+	LamportsPerSignature uint64
+}
+
+type RecentBlockhash struct {
+	Blockhash            string
+	LamportsPerSignature uint64
+}
+
+type SlotHash struct {
+	Slot uint64
+	Hash string
+}
+
+type StakeHistoryEntry struct {
+	Epoch        uint64
+	Effective    uint64
+	Activating   uint64
+	Deactivating uint64
+}
+
+type EpochRewards struct {
+	DistributionStartingBlockHeight uint64
+	NumPartitions                   uint64
+	ParentBlockhash                 string
+	TotalPoints                     *big.Int // u128
+	TotalRewards                    uint64
+	DistributedRewards              uint64
+	Active                          bool
+}
+
+type LastRestartSlot struct {
+	LastRestartSlot uint64
+}
+
+/*
+clock数据：u64 slot | i64 epoch start timestamp | u64 epoch | u64 leader schedule epoch | i64 unix timestamp
+*/
+func DeserializeClock(data []byte) (*Clock, error) {
+	if err := checkSize("clock", data, ClockSize); err != nil {
+		return nil, err
+	}
+	return &Clock{
+		Slot:                binary.LittleEndian.Uint64(data[0:8]),
+		EpochStartTimestamp: int64(binary.LittleEndian.Uint64(data[8:16])),
+		Epoch:               binary.LittleEndian.Uint64(data[16:24]),
+		LeaderScheduleEpoch: binary.LittleEndian.Uint64(data[24:32]),
+		UnixTimestamp:       int64(binary.LittleEndian.Uint64(data[32:40])),
+	}, nil
+}
+
+/*
+rent数据：u64 lamports per byte year | f64 exemption threshold | u8 burn percent
+*/
+func DeserializeRent(data []byte) (*Rent, error) {
+	if err := checkSize("rent", data, RentSize); err != nil {
+		return nil, err
+	}
+	return &Rent{
+		LamportsPerByteYear: binary.LittleEndian.Uint64(data[0:8]),
+		ExemptionThreshold:  math.Float64frombits(binary.LittleEndian.Uint64(data[8:16])),
+		BurnPercent:         data[16],
+	}, nil
+}
+
+/*
+epoch schedule数据：u64 slots per epoch | u64 leader schedule slot offset | bool warmup | u64 first normal epoch | u64 first normal slot
+*/
+func DeserializeEpochSchedule(data []byte) (*EpochSchedule, error) {
+	if err := checkSize("epoch schedule", data, EpochScheduleSize); err != nil {
+		return nil, err
+	}
+	warmup, err := readBool(data[16])
+	if err != nil {
+		return nil, err
+	}
+	return &EpochSchedule{
+		SlotsPerEpoch:            binary.LittleEndian.Uint64(data[0:8]),
+		LeaderScheduleSlotOffset: binary.LittleEndian.Uint64(data[8:16]),
+		Warmup:                   warmup,
+		FirstNormalEpoch:         binary.LittleEndian.Uint64(data[17:25]),
+		FirstNormalSlot:          binary.LittleEndian.Uint64(data[25:33]),
+	}, nil
+}
+
+func DeserializeFees(data []byte) (*Fees, error) {
+	if err := checkSize("fees", data, FeesSize); err != nil {
+		return nil, err
+	}
+	return &Fees{LamportsPerSignature: binary.LittleEndian.Uint64(data[0:8])}, nil
+}
+
+/*
+recent blockhashes数据：u64 数量 | (hash | u64 lamports per signature)...，最新的在最前
+*/
+func DeserializeRecentBlockhashes(data []byte) ([]RecentBlockhash, error) {
+	entries, err := readVector("recent blockhashes", data, 40)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]RecentBlockhash, len(entries))
+	for i, entry := range entries {
+		result[i] = RecentBlockhash{
+			Blockhash:            base58.Encode(entry[0:32]),
+			LamportsPerSignature: binary.LittleEndian.Uint64(entry[32:40]),
+		}
+	}
+	return result, nil
+}
+
+/*
+slot hashes数据：u64 数量 | (u64 slot | hash)...，账户数据末尾有填充
+*/
+func DeserializeSlotHashes(data []byte) ([]SlotHash, error) {
+	entries, err := readVector("slot hashes", data, 40)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]SlotHash, len(entries))
+	for i, entry := range entries {
+		result[i] = SlotHash{
+			Slot: binary.LittleEndian.Uint64(entry[0:8]),
+			Hash: base58.Encode(entry[8:40]),
+		}
+	}
+	return result, nil
+}
+
+/*
+stake history数据：u64 数量 | (u64 epoch | u64 effective | u64 activating | u64 deactivating)...
+*/
+func DeserializeStakeHistory(data []byte) ([]StakeHistoryEntry, error) {
+	entries, err := readVector("stake history", data, 32)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]StakeHistoryEntry, len(entries))
+	for i, entry := range entries {
+		result[i] = StakeHistoryEntry{
+			Epoch:        binary.LittleEndian.Uint64(entry[0:8]),
+			Effective:    binary.LittleEndian.Uint64(entry[8:16]),
+			Activating:   binary.LittleEndian.Uint64(entry[16:24]),
+			Deactivating: binary.LittleEndian.Uint64(entry[24:32]),
+		}
+	}
+	return result, nil
+}
+
+/*
+epoch rewards数据：u64 starting block height | u64 partitions | parent blockhash | u128 total points
+| u64 total rewards | u64 distributed rewards | bool active
+*/
+func DeserializeEpochRewards(data []byte) (*EpochRewards, error) {
+	if err := checkSize("epoch rewards", data, EpochRewardsSize); err != nil {
+		return nil, err
+	}
+	active, err := readBool(data[80])
+	if err != nil {
+		return nil, err
+	}
+	// u128小端序，转为大端序给big.Int
+	totalPoints := make([]byte, 16)
+	for i := 0; i < 16; i++ {
+		totalPoints[i] = data[63-i]
+	}
+	return &EpochRewards{
+		DistributionStartingBlockHeight: binary.LittleEndian.Uint64(data[0:8]),
+		NumPartitions:                   binary.LittleEndian.Uint64(data[8:16]),
+		ParentBlockhash:                 base58.Encode(data[16:48]),
+		TotalPoints:                     new(big.Int).SetBytes(totalPoints),
+		TotalRewards:                    binary.LittleEndian.Uint64(data[64:72]),
+		DistributedRewards:              binary.LittleEndian.Uint64(data[72:80]),
+		Active:                          active,
+	}, nil
+}
+
+func DeserializeLastRestartSlot(data []byte) (*LastRestartSlot, error) {
+	if err := checkSize("last restart slot", data, LastRestartSlotSize); err != nil {
+		return nil, err
+	}
+	return &LastRestartSlot{LastRestartSlot: binary.LittleEndian.Uint64(data[0:8])}, nil
+}
+
+func GetClock(client *rpc.RpcClient) (*Clock, error) {
+	data, err := getSysvarData(client, ClockPubkey)
+	if err != nil {
+		return nil, err
+	}
+	return DeserializeClock(data)
+}
+
+func GetRent(client *rpc.RpcClient) (*Rent, error) {
+	data, err := getSysvarData(client, RentPubkey)
+	if err != nil {
+		return nil, err
+	}
+	return DeserializeRent(data)
+}
+
+func GetEpochSchedule(client *rpc.RpcClient) (*EpochSchedule, error) {
+	data, err := getSysvarData(client, EpochSchedulePubkey)
+	if err != nil {
+		return nil, err
+	}
+	return DeserializeEpochSchedule(data)
+}
+
+func GetFees(client *rpc.RpcClient) (*Fees, error) {
+	data, err := getSysvarData(client, FeesPubkey)
+	if err != nil {
+		return nil, err
+	}
+	return DeserializeFees(data)
+}
+
+func GetRecentBlockhashes(client *rpc.RpcClient) ([]RecentBlockhash, error) {
+	data, err := getSysvarData(client, RecentBlockhashesPubkey)
+	if err != nil {
+		return nil, err
+	}
+	return DeserializeRecentBlockhashes(data)
+}
+
+func GetSlotHashes(client *rpc.RpcClient) ([]SlotHash, error) {
+	data, err := getSysvarData(client, SlotHashesPubkey)
+	if err != nil {
+		return nil, err
+	}
+	return DeserializeSlotHashes(data)
+}
+
+func GetStakeHistory(client *rpc.RpcClient) ([]StakeHistoryEntry, error) {
+	data, err := getSysvarData(client, StakeHistoryPubkey)
+	if err != nil {
+		return nil, err
+	}
+	return DeserializeStakeHistory(data)
+}
+
+func GetEpochRewards(client *rpc.RpcClient) (*EpochRewards, error) {
+	data, err := getSysvarData(client, EpochRewardsPubkey)
+	if err != nil {
+		return nil, err
+	}
+	return DeserializeEpochRewards(data)
+}
+
+func GetLastRestartSlot(client *rpc.RpcClient) (*LastRestartSlot, error) {
+	data, err := getSysvarData(client, LastRestartSlotPubkey)
+	if err != nil {
+		return nil, err
+	}
+	return DeserializeLastRestartSlot(data)
+}
+
+func getSysvarData(client *rpc.RpcClient, pubkey account.PublicKey) ([]byte, error) {
+	info, err := client.GetAccountInfo(pubkey)
+	if err != nil {
+		return nil, err
+	}
+	if info == nil {
+		return nil, fmt.Errorf("sysvar [%s] is not found", pubkey.String())
+	}
+	if info.Owner != OwnerId {
+		return nil, fmt.Errorf("account [%s] is not owned by sysvar", pubkey.String())
+	}
+	return info.Data, nil
+}
+
+func checkSize(name string, data []byte, size int) error {
+	if len(data) < size {
+		return fmt.Errorf("%s data length is %d, less than %d", name, len(data), size)
+	}
+	return nil
+}
+
+/*
+bincode的Vec：u64 数量 + 定长元素，返回每个元素的切片
+*/
+func readVector(name string, data []byte, entrySize int) ([][]byte, error) {
+	if err := checkSize(name, data, 8); err != nil {
+		return nil, err
+	}
+	count := binary.LittleEndian.Uint64(data[0:8])
+	if count > uint64(len(data)-8)/uint64(entrySize) {
+		return nil, fmt.Errorf("%s entries count [%d] is not valid", name, count)
+	}
+	entries := make([][]byte, count)
+	for i := range entries {
+		offset := 8 + i*entrySize
+		entries[i] = data[offset : offset+entrySize]
+	}
+	return entries, nil
+}
+
+func readBool(b byte) (bool, error) {
+	switch b {
+	case 0:
+		return false, nil
+	case 1:
+		return true, nil
+	}
+	return false, fmt.Errorf("bool value [%d] is not valid", b)
+}
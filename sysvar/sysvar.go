@@ -0,0 +1,26 @@
+package sysvar
+
+/*
+func：
+author： flynn
+date: 2020-08-03
+fork: https://github.com/solana-labs/solana-web3.js/src/sysvar.js
+*/
+import "github.com/JFJun/solana-go/account"
+
+var (
+	ClockPubkey             = account.MustPublicKeyFromBase58("SysvarC1ock11111111111111111111111111111111")
+	RentPubkey              = account.MustPublicKeyFromBase58("SysvarRent111111111111111111111111111111111")
+	EpochSchedulePubkey     = account.MustPublicKeyFromBase58("SysvarEpochSchedu1e111111111111111111111111")
+	FeesPubkey              = account.MustPublicKeyFromBase58("SysvarFees111111111111111111111111111111111")
+	RecentBlockhashesPubkey = account.MustPublicKeyFromBase58("SysvarRecentB1ockHashes11111111111111111111")
+	SlotHashesPubkey        = account.MustPublicKeyFromBase58("SysvarS1otHashes111111111111111111111111111")
+	SlotHistoryPubkey       = account.MustPublicKeyFromBase58("SysvarS1otHistory11111111111111111111111111")
+	StakeHistoryPubkey      = account.MustPublicKeyFromBase58("SysvarStakeHistory1111111111111111111111111")
+	InstructionsPubkey      = account.MustPublicKeyFromBase58("Sysvar1nstructions1111111111111111111111111")
+	EpochRewardsPubkey      = account.MustPublicKeyFromBase58("SysvarEpochRewards1111111111111111111111111")
+	LastRestartSlotPubkey   = account.MustPublicKeyFromBase58("SysvarLastRestartS1ot1111111111111111111111")
+
+	// 所有sysvar账户的owner
+	OwnerId = account.MustPublicKeyFromBase58("Sysvar1111111111111111111111111111111111111")
+)
@@ -0,0 +1,115 @@
+package test
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"testing"
+
+	"github.com/JFJun/solana-go/account"
+	"github.com/JFJun/solana-go/addresslookuptable"
+	"github.com/JFJun/solana-go/rpc"
+	"github.com/JFJun/solana-go/systemprogram"
+)
+
+func Test_CreateLookupTable(t *testing.T) {
+	authority := account.MustPublicKeyFromBase58("9SvsEyncSPjZaqjEsGjfvgaQowxq1BTNTJo6imGxseyx")
+	ins, lookupTable, err := addresslookuptable.NewCreateLookupTable(addresslookuptable.CreateLookupTableParams{
+		Authority:  authority,
+		Payer:      authority,
+		RecentSlot: 123456,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	slot := make([]byte, 8)
+	binary.LittleEndian.PutUint64(slot, 123456)
+	expect, bump, err := account.FindProgramAddress([][]byte{authority[:], slot}, addresslookuptable.ProgramId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !lookupTable.Equals(expect) {
+		t.Fatal("lookup table address error")
+	}
+	data := ins.GetData()
+	if len(data) != 13 || data[0] != 0 || binary.LittleEndian.Uint64(data[4:12]) != 123456 || data[12] != bump {
+		t.Fatalf("create lookup table data error,got=%v", data)
+	}
+	keys := ins.GetKeys()
+	if len(keys) != 4 || !keys[0].PubKey.Equals(lookupTable) || !keys[3].PubKey.Equals(systemprogram.ProgramId) {
+		t.Fatal("create lookup table keys error")
+	}
+
+	extend, err := addresslookuptable.NewExtendLookupTable(addresslookuptable.ExtendLookupTableParams{
+		LookupTable: lookupTable,
+		Authority:   authority,
+		Payer:       &authority,
+		Addresses:   []account.PublicKey{authority, systemprogram.ProgramId},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(extend.GetKeys()) != 4 || len(extend.GetData()) != 4+8+64 || extend.GetData()[4] != 2 {
+		t.Fatal("extend lookup table error")
+	}
+}
+
+func newLookupTableData(authority *account.PublicKey, addresses []account.PublicKey) []byte {
+	data := make([]byte, addresslookuptable.LookupTableMetaSize)
+	binary.LittleEndian.PutUint32(data[0:4], 1)
+	binary.LittleEndian.PutUint64(data[4:12], addresslookuptable.ActiveDeactivationSlot)
+	binary.LittleEndian.PutUint64(data[12:20], 100)
+	if authority != nil {
+		data[21] = 1
+		copy(data[22:54], authority[:])
+	}
+	for _, addr := range addresses {
+		data = append(data, addr[:]...)
+	}
+	return data
+}
+
+func Test_DeserializeLookupTable(t *testing.T) {
+	authority := account.MustPublicKeyFromBase58("9SvsEyncSPjZaqjEsGjfvgaQowxq1BTNTJo6imGxseyx")
+	addresses := []account.PublicKey{
+		account.MustPublicKeyFromBase58("BHUNqtk5Vv6vfQTxpPjqWo2v8GPZJbqBonCaqhhK1Hub"),
+		systemprogram.ProgramId,
+	}
+	state, err := addresslookuptable.DeserializeLookupTable(newLookupTableData(&authority, addresses))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !state.IsActive() || state.LastExtendedSlot != 100 || state.Authority == nil || !state.Authority.Equals(authority) {
+		t.Fatalf("lookup table meta error,got=%+v", state)
+	}
+	if len(state.Addresses) != 2 || !state.Addresses[0].Equals(addresses[0]) {
+		t.Fatal("lookup table addresses error")
+	}
+	frozen, err := addresslookuptable.DeserializeLookupTable(newLookupTableData(nil, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if frozen.Authority != nil || len(frozen.Addresses) != 0 {
+		t.Fatal("frozen lookup table error")
+	}
+	if _, err := addresslookuptable.DeserializeLookupTable(make([]byte, 56)); err == nil {
+		t.Fatal("uninitialized lookup table should fail")
+	}
+}
+
+func Test_GetLookupTable(t *testing.T) {
+	key := account.MustPublicKeyFromBase58("BHUNqtk5Vv6vfQTxpPjqWo2v8GPZJbqBonCaqhhK1Hub")
+	addresses := []account.PublicKey{systemprogram.ProgramId}
+	data := base64.StdEncoding.EncodeToString(newLookupTableData(nil, addresses))
+	server := newMockRpcServer(t, map[string]string{
+		"getAccountInfo": `{"context":{"slot":1},"value":{"data":["` + data + `","base64"],"executable":false,` +
+			`"lamports":1,"owner":"AddressLookupTab1e1111111111111111111111111","rentEpoch":0}}`,
+	})
+	defer server.Close()
+	table, err := addresslookuptable.GetLookupTable(rpc.New(server.URL, "", ""), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !table.Key.Equals(key) || len(table.Addresses) != 1 {
+		t.Fatal("get lookup table error")
+	}
+}
@@ -0,0 +1,115 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/JFJun/solana-go/account"
+	"github.com/JFJun/solana-go/associatedtoken"
+	"github.com/JFJun/solana-go/systemprogram"
+	"github.com/JFJun/solana-go/token"
+)
+
+var usdcMint = account.MustPublicKeyFromBase58("EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v")
+
+func Test_FindAssociatedTokenAddress(t *testing.T) {
+	wallet, _ := newTestAccounts()
+	ata, bump, err := associatedtoken.FindAssociatedTokenAddress(wallet.GetPublicKey(), usdcMint, account.PublicKey{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ata.IsOnCurve() {
+		t.Fatal("associated token address should be off curve")
+	}
+	expect, err := account.CreateProgramAddress([][]byte{
+		wallet.PublicKey, token.ProgramId.Bytes(), usdcMint.Bytes(), {bump},
+	}, associatedtoken.ProgramId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ata.Equals(expect) {
+		t.Fatal("associated token address error")
+	}
+	other, _, err := associatedtoken.FindAssociatedTokenAddress(wallet.GetPublicKey(), usdcMint, newTestPublicKey(9))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.Equals(ata) {
+		t.Fatal("token program should be part of the seeds")
+	}
+}
+
+func Test_CreateAssociatedTokenAccount(t *testing.T) {
+	payer, wallet := newTestAccounts()
+	ata, _, err := associatedtoken.FindAssociatedTokenAddress(wallet.GetPublicKey(), usdcMint, token.ProgramId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	params := associatedtoken.CreateParams{Payer: payer.GetPublicKey(), Wallet: wallet.GetPublicKey(), Mint: usdcMint}
+	metas := []expectMeta{
+		{payer.GetPublicKey(), true, true},
+		{ata, false, true},
+		{wallet.GetPublicKey(), false, false},
+		{usdcMint, false, false},
+		{systemprogram.ProgramId, false, false},
+		{token.ProgramId, false, false},
+	}
+	ins, err := associatedtoken.NewCreateAssociatedTokenAccount(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "create ata", ins, associatedtoken.ProgramId, metas, "00")
+	ins, err = associatedtoken.NewCreateAssociatedTokenAccountIdempotent(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "create ata idempotent", ins, associatedtoken.ProgramId, metas, "01")
+}
+
+func Test_RecoverNested(t *testing.T) {
+	wallet, _ := newTestAccounts()
+	ownerMint, nestedMint := newTestPublicKey(1), newTestPublicKey(2)
+	ownerAta, _, _ := associatedtoken.FindAssociatedTokenAddress(wallet.GetPublicKey(), ownerMint, token.ProgramId)
+	nestedAta, _, _ := associatedtoken.FindAssociatedTokenAddress(ownerAta, nestedMint, token.ProgramId)
+	destAta, _, _ := associatedtoken.FindAssociatedTokenAddress(wallet.GetPublicKey(), nestedMint, token.ProgramId)
+	ins, err := associatedtoken.NewRecoverNested(associatedtoken.RecoverNestedParams{
+		Wallet: wallet.GetPublicKey(), OwnerMint: ownerMint, NestedMint: nestedMint,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "recover nested", ins, associatedtoken.ProgramId, []expectMeta{
+		{nestedAta, false, true},
+		{nestedMint, false, false},
+		{destAta, false, true},
+		{ownerAta, false, false},
+		{ownerMint, false, false},
+		{wallet.GetPublicKey(), true, true},
+		{token.ProgramId, false, false},
+	}, "02")
+}
+
+func Test_TransferToWallet(t *testing.T) {
+	owner, to := newTestAccounts()
+	ins, err := associatedtoken.NewTransferToWallet(associatedtoken.TransferToWalletParams{
+		Payer:    owner.GetPublicKey(),
+		Owner:    owner.GetPublicKey(),
+		ToWallet: to.GetPublicKey(),
+		Mint:     usdcMint,
+		Amount:   1000000,
+		Decimals: 6,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ins) != 2 {
+		t.Fatalf("instruction length error,got=%d", len(ins))
+	}
+	source, _, _ := associatedtoken.FindAssociatedTokenAddress(owner.GetPublicKey(), usdcMint, token.ProgramId)
+	dest, _, _ := associatedtoken.FindAssociatedTokenAddress(to.GetPublicKey(), usdcMint, token.ProgramId)
+	if !ins[0].GetKeys()[1].PubKey.Equals(dest) {
+		t.Fatal("create destination ata error")
+	}
+	checkInstruction(t, "transfer checked", ins[1], token.ProgramId, []expectMeta{
+		{source, false, true}, {usdcMint, false, false}, {dest, false, true}, {owner.GetPublicKey(), true, false},
+	}, "0c40420f000000000006")
+}
@@ -0,0 +1,76 @@
+package test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/JFJun/solana-go/account"
+	"github.com/JFJun/solana-go/transaction"
+)
+
+/*
+模拟批量打款：一个付款账户向多个地址转账
+*/
+func newBatchPayoutTx(b *testing.B, numTransfers int) *transaction.Transaction {
+	payer, _ := newTestAccounts()
+	tx := transaction.NewTransaction("EETubP5AKHgjPAhzPAFcb8BAY1hMH639CWCFTqi3hq1k")
+	tx.FeePayer = payer.GetPublicKey()
+	for i := 0; i < numTransfers; i++ {
+		to := newTestPublicKey(byte(i + 1))
+		transfer, err := transaction.NewTransfer(transaction.TransferParams{
+			From:   payer.GetPublicKey(),
+			To:     to,
+			Amount: big.NewInt(int64(i + 1)),
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+		tx.SetInstructions(transfer)
+	}
+	return tx
+}
+
+func benchmarkCompileMessage(b *testing.B, numTransfers int) {
+	tx := newBatchPayoutTx(b, numTransfers)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := tx.CompileMessage(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkMessageSerialize(b *testing.B, numTransfers int) {
+	tx := newBatchPayoutTx(b, numTransfers)
+	message, err := tx.CompileMessage()
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		message.Serialize()
+	}
+}
+
+func Benchmark_CompileMessage10(b *testing.B)  { benchmarkCompileMessage(b, 10) }
+func Benchmark_CompileMessage200(b *testing.B) { benchmarkCompileMessage(b, 200) }
+
+func Benchmark_MessageSerialize10(b *testing.B)  { benchmarkMessageSerialize(b, 10) }
+func Benchmark_MessageSerialize200(b *testing.B) { benchmarkMessageSerialize(b, 200) }
+
+func Benchmark_SignAndSerialize(b *testing.B) {
+	payer, _ := newTestAccounts()
+	tx := newBatchPayoutTx(b, 20)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := tx.Sign([]*account.Account{payer}); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := tx.Serialize(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/JFJun/solana-go/account"
+	"github.com/JFJun/solana-go/bpfloader"
+	"github.com/JFJun/solana-go/systemprogram"
+	"github.com/JFJun/solana-go/sysvar"
+	"github.com/JFJun/solana-go/transaction"
+)
+
+func Test_BpfLoaderInstructions(t *testing.T) {
+	var (
+		payer     = newTestPublicKey(1)
+		program   = newTestPublicKey(2)
+		buffer    = newTestPublicKey(3)
+		authority = newTestPublicKey(4)
+		spill     = newTestPublicKey(5)
+		newAuth   = newTestPublicKey(6)
+	)
+	programData, bump, err := bpfloader.FindProgramDataAddress(program)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectPda, err := account.CreateProgramAddress([][]byte{program[:], {bump}}, bpfloader.ProgramId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !programData.Equals(expectPda) {
+		t.Fatal("program data address error")
+	}
+
+	ins, err := bpfloader.NewInitializeBuffer(bpfloader.InitializeBufferParams{Buffer: buffer, Authority: authority})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "initialize buffer", ins, bpfloader.ProgramId, []expectMeta{
+		{buffer, false, true}, {authority, false, false},
+	}, "00000000")
+
+	ins, err = bpfloader.NewWrite(bpfloader.WriteParams{Buffer: buffer, Authority: authority, Offset: 300, Bytes: []byte{0xaa, 0xbb}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "write", ins, bpfloader.ProgramId, []expectMeta{
+		{buffer, false, true}, {authority, true, false},
+	}, "01000000"+"2c010000"+"0200000000000000"+"aabb")
+
+	ins, err = bpfloader.NewDeployWithMaxDataLen(bpfloader.DeployWithMaxDataLenParams{
+		Payer: payer, Program: program, Buffer: buffer, Authority: authority, MaxDataLen: 4096,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "deploy", ins, bpfloader.ProgramId, []expectMeta{
+		{payer, true, true}, {programData, false, true}, {program, false, true}, {buffer, false, true},
+		{sysvar.RentPubkey, false, false}, {sysvar.ClockPubkey, false, false},
+		{systemprogram.ProgramId, false, false}, {authority, true, false},
+	}, "02000000"+"0010000000000000")
+
+	ins, err = bpfloader.NewUpgrade(bpfloader.UpgradeParams{Program: program, Buffer: buffer, Spill: spill, Authority: authority})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "upgrade", ins, bpfloader.ProgramId, []expectMeta{
+		{programData, false, true}, {program, false, true}, {buffer, false, true}, {spill, false, true},
+		{sysvar.RentPubkey, false, false}, {sysvar.ClockPubkey, false, false}, {authority, true, false},
+	}, "03000000")
+
+	ins, err = bpfloader.NewSetAuthority(bpfloader.SetAuthorityParams{Account: programData, CurrentAuthority: authority, NewAuthority: &newAuth})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "set authority", ins, bpfloader.ProgramId, []expectMeta{
+		{programData, false, true}, {authority, true, false}, {newAuth, false, false},
+	}, "04000000")
+	// 不传新authority时程序变为不可升级
+	ins, err = bpfloader.NewSetAuthority(bpfloader.SetAuthorityParams{Account: programData, CurrentAuthority: authority})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "set authority none", ins, bpfloader.ProgramId, []expectMeta{
+		{programData, false, true}, {authority, true, false},
+	}, "04000000")
+	ins, err = bpfloader.NewSetAuthorityChecked(bpfloader.SetAuthorityParams{Account: buffer, CurrentAuthority: authority, NewAuthority: &newAuth})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "set authority checked", ins, bpfloader.ProgramId, []expectMeta{
+		{buffer, false, true}, {authority, true, false}, {newAuth, true, false},
+	}, "07000000")
+	if _, err := bpfloader.NewSetAuthorityChecked(bpfloader.SetAuthorityParams{Account: buffer, CurrentAuthority: authority}); err == nil {
+		t.Fatal("set authority checked without new authority should fail")
+	}
+
+	ins, err = bpfloader.NewClose(bpfloader.CloseParams{Account: buffer, Recipient: payer, Authority: authority})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "close buffer", ins, bpfloader.ProgramId, []expectMeta{
+		{buffer, false, true}, {payer, false, true}, {authority, true, false},
+	}, "05000000")
+	ins, err = bpfloader.NewClose(bpfloader.CloseParams{Account: programData, Recipient: payer, Authority: authority, Program: &program})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "close program", ins, bpfloader.ProgramId, []expectMeta{
+		{programData, false, true}, {payer, false, true}, {authority, true, false}, {program, false, true},
+	}, "05000000")
+
+	ins, err = bpfloader.NewExtendProgram(bpfloader.ExtendProgramParams{Program: program, Payer: &payer, AdditionalBytes: 1024})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "extend program", ins, bpfloader.ProgramId, []expectMeta{
+		{programData, false, true}, {program, false, true}, {systemprogram.ProgramId, false, false}, {payer, true, true},
+	}, "06000000"+"00040000")
+}
+
+func Test_BpfLoaderDeployHelpers(t *testing.T) {
+	var (
+		payer     = newTestPublicKey(1)
+		program   = newTestPublicKey(2)
+		buffer    = newTestPublicKey(3)
+		authority = newTestPublicKey(4)
+	)
+	ins, err := bpfloader.NewCreateBuffer(bpfloader.CreateBufferParams{
+		Payer: payer, Buffer: buffer, Authority: authority, Lamports: 1, ProgramLen: 1000,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	create := ins[0].GetData()
+	if len(ins) != 2 || binary.LittleEndian.Uint64(create[12:20]) != 1037 || !bytes.Equal(create[20:52], bpfloader.ProgramId.Bytes()) {
+		t.Fatal("create buffer error")
+	}
+	ins, err = bpfloader.NewDeployProgram(bpfloader.DeployProgramParams{
+		Payer: payer, Program: program, Buffer: buffer, Authority: authority, Lamports: 1, MaxDataLen: 2000,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	create = ins[0].GetData()
+	if len(ins) != 2 || binary.LittleEndian.Uint64(create[12:20]) != bpfloader.ProgramAccountSize ||
+		hex.EncodeToString(ins[1].GetData()) != "02000000d007000000000000" {
+		t.Fatal("deploy program error")
+	}
+}
+
+func Test_BpfLoaderWriteTransactions(t *testing.T) {
+	payer, authority := newTestAccounts()
+	buffer := newTestPublicKey(3)
+	elf := make([]byte, 5000)
+	for i := range elf {
+		elf[i] = byte(i * 7)
+	}
+	path := filepath.Join(os.TempDir(), "bpfloader_test_program.so")
+	if err := ioutil.WriteFile(path, elf, 0644); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(path)
+	txs, err := bpfloader.NewWriteTransactionsFromFile(path, bpfloader.WriteTransactionsParams{
+		Payer:           payer.GetPublicKey(),
+		Buffer:          buffer,
+		Authority:       authority.GetPublicKey(),
+		RecentBlockHash: "EETubP5AKHgjPAhzPAFcb8BAY1hMH639CWCFTqi3hq1k",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	chunkSize, err := bpfloader.WriteChunkSize(payer.GetPublicKey(), buffer, authority.GetPublicKey(), "EETubP5AKHgjPAhzPAFcb8BAY1hMH639CWCFTqi3hq1k")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != (len(elf)+chunkSize-1)/chunkSize {
+		t.Fatalf("write transactions count error,got=%d", len(txs))
+	}
+	assembled := make([]byte, len(elf))
+	for i, tx := range txs {
+		if err := tx.Sign([]*account.Account{payer, authority}); err != nil {
+			t.Fatal(err)
+		}
+		wireTx, err := tx.Serialize()
+		if err != nil {
+			t.Fatal(err)
+		}
+		// 除最后一块外每个交易都应正好填满
+		if i < len(txs)-1 && len(wireTx) != transaction.PACK_DATA_SIZE {
+			t.Fatalf("write transaction %d size error,got=%d", i, len(wireTx))
+		}
+		data := tx.Instructions[0].GetData()
+		offset := binary.LittleEndian.Uint32(data[4:8])
+		length := binary.LittleEndian.Uint64(data[8:16])
+		copy(assembled[offset:], data[16:16+length])
+	}
+	if !bytes.Equal(assembled, elf) {
+		t.Fatal("write transactions do not reassemble program data")
+	}
+	if _, err := bpfloader.NewWriteTransactions(bpfloader.WriteTransactionsParams{Payer: payer.GetPublicKey()}); err == nil {
+		t.Fatal("empty program data should fail")
+	}
+}
@@ -0,0 +1,288 @@
+package test
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"math/big"
+	"testing"
+
+	"github.com/JFJun/solana-go/account"
+	"github.com/JFJun/solana-go/computebudget"
+	"github.com/JFJun/solana-go/memo"
+	"github.com/JFJun/solana-go/token"
+	"github.com/JFJun/solana-go/transaction"
+)
+
+/*
+golden vector来自web3.js的transaction.test.ts（serialize），签名是确定性的，所以wire数据必须逐字节一致
+*/
+const web3SerializeVector = "AVuErQHaXv0SG0/PchunfxHKt8wMRfMZzqV0tkC5qO6owYxWU2v871AoWywGoFQr4z+q/7mE8lIufNl/kxj+nQ0BAAED" +
+	"E5j2LG0aRXxRumpLXz29L2n8qTIWIY3ImX5Ba9F9k8r9Q5/Mtmcn8onFxt47xKj+XdXXd3C8j/FcPu7csUrz/AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA" +
+	"AAAAAAAAxJrndgN4IFTxep3s6kO0ROug7bEsbx0xxuDkqEvwUusBAgIAAQwCAAAAMQAAAAAAAAA="
+
+func newWeb3VectorTx(t *testing.T) (*transaction.Transaction, *account.Account) {
+	sender := account.NewAccountBySecret(bytes.Repeat([]byte{8}, 32))
+	recipient := account.MustPublicKeyFromBase58("J3dxNj7nDRRqRRXuEMynDG57DkZK4jYRuv3Garmb1i99")
+	transfer, err := transaction.NewTransfer(transaction.TransferParams{
+		From:   sender.GetPublicKey(),
+		To:     recipient,
+		Amount: big.NewInt(49),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := transaction.NewTransaction("EETubP5AKHgjPAhzPAFcb8BAY1hMH639CWCFTqi3hq1k")
+	tx.FeePayer = sender.GetPublicKey()
+	tx.SetInstructions(transfer)
+	return tx, sender
+}
+
+func Test_Web3SerializeVector(t *testing.T) {
+	expect, err := base64.StdEncoding.DecodeString(web3SerializeVector)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, sender := newWeb3VectorTx(t)
+	if err := tx.Sign([]*account.Account{sender}); err != nil {
+		t.Fatal(err)
+	}
+	wireTx, err := tx.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(wireTx, expect) {
+		t.Fatalf("serialize is not equal to web3.js,got=%s", base64.StdEncoding.EncodeToString(wireTx))
+	}
+
+	// 未签名时签名位置为64个0字节，其余部分不变
+	unsigned, _ := newWeb3VectorTx(t)
+	unsignedWireTx, err := unsigned.SerializeWithConfig(transaction.SerializeConfig{RequireAllSignatures: false})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectUnsigned := append([]byte{1}, make([]byte, ed25519.SignatureSize)...)
+	expectUnsigned = append(expectUnsigned, expect[1+ed25519.SignatureSize:]...)
+	if !bytes.Equal(unsignedWireTx, expectUnsigned) {
+		t.Fatal("unsigned serialize is not equal to web3.js")
+	}
+}
+
+func newTestPublicKey(b byte) account.PublicKey {
+	var pk account.PublicKey
+	for i := range pk {
+		pk[i] = b
+	}
+	return pk
+}
+
+/*
+按web3.js compileMessage的规则推导：去重时合并标记，按签名/可写分组，组内按base58的localeCompare排序，手续费支付者在最前
+这里的期望值是手工推导的：newTestPublicKey(2)<(3)、(6)<(7)的base58顺序与出现顺序相同，真实的web3.js向量见Test_Web3MultiInstructionVectors
+*/
+func Test_CompileMessageOrdering(t *testing.T) {
+	var (
+		feePayer  = newTestPublicKey(1)
+		readonly  = newTestPublicKey(2) // 第一个指令中只读，第二个指令中可写
+		writable  = newTestPublicKey(3)
+		signerRo  = newTestPublicKey(4)
+		signerW   = newTestPublicKey(5)
+		programA  = newTestPublicKey(6)
+		programB  = newTestPublicKey(7)
+		insAMetas = []*transaction.AccountMeta{
+			transaction.NewAccountMeta(readonly, false, false),
+			transaction.NewAccountMeta(writable, false, true),
+			transaction.NewAccountMeta(signerRo, true, false),
+		}
+	)
+	insA, err := transaction.NewTransactionInstruction(programA, insAMetas, []byte{1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	insB, err := transaction.NewTransactionInstruction(programB, []*transaction.AccountMeta{
+		transaction.NewAccountMeta(readonly, false, true),
+		transaction.NewAccountMeta(signerW, true, true),
+		transaction.NewAccountMeta(programA, false, false),
+	}, []byte{2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := transaction.NewTransaction("EETubP5AKHgjPAhzPAFcb8BAY1hMH639CWCFTqi3hq1k")
+	tx.FeePayer = feePayer
+	tx.SetInstructions(insA)
+	tx.SetInstructions(insB)
+	message, err := tx.CompileMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectKeys := []account.PublicKey{feePayer, signerW, signerRo, readonly, writable, programA, programB}
+	if len(message.AccountKeys) != len(expectKeys) {
+		t.Fatalf("account keys length error,got=%d", len(message.AccountKeys))
+	}
+	for i, k := range expectKeys {
+		if !message.AccountKeys[i].Equals(k) {
+			t.Fatalf("account key %d error,expect=%s,got=%s", i, k.String(), message.AccountKeys[i].String())
+		}
+	}
+	expectHeader := transaction.MessageHeader{
+		NumRequiredSignatures:       3,
+		NumReadonlySignedAccounts:   1,
+		NumReadonlyUnsignedAccounts: 2,
+	}
+	if *message.Header != expectHeader {
+		t.Fatalf("message header error,got=%+v", *message.Header)
+	}
+	if message.Instructions[0].ProgramIdIndex != 5 || message.Instructions[1].ProgramIdIndex != 6 {
+		t.Fatal("program id index error")
+	}
+	if !bytes.Equal(intsToBytes(message.Instructions[0].Accounts), []byte{3, 4, 2}) ||
+		!bytes.Equal(intsToBytes(message.Instructions[1].Accounts), []byte{3, 1, 5}) {
+		t.Fatal("instruction accounts error")
+	}
+	// 编译不能修改指令中的AccountMeta
+	if insAMetas[0].IsWriteable || insAMetas[0].IsSigner {
+		t.Fatal("instruction account meta should not be modified")
+	}
+	// 多次编译结果一致
+	again, err := tx.CompileMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(again.Serialize(), message.Serialize()) {
+		t.Fatal("compile message is not deterministic")
+	}
+}
+
+func intsToBytes(ints []int) []byte {
+	var data []byte
+	for _, i := range ints {
+		data = append(data, byte(i))
+	}
+	return data
+}
+
+/*
+以下golden vector由test/testdata/web3_vectors.js生成，每个分组中都有多个账户，用来覆盖组内的base58排序
+*/
+const (
+	web3TokenTransferWithMemoVector = "BGJpy1FYQ6QKm8m9ceneRE0ez494J0fkc1yQQ3Yj5elCN6J4TPD1rZkdL9MCAZQQZBUQsY2Zs7IyYWgmsaUhZgftGVAS9Js/E0SPQp22aU7OVqsOg1Pk78vw5donPr1F8GZcr2leSl45nrpN9DXQikm3KzG/YcCZq/Z1M/VFLWYCYP0njpP7PLjMwJfI+/Tk2RJPQJErdzy7hE8jMzw6o/KMm4RNf6Vel0MtyUz68dKW4MrF8Qrwk/ol9cmMXzQFCdwUJ2NX4TWqC9G5s7EQYjnDq9PrFz3ywFOS6dwY5nZbUTdDuyu6oMahLlho2n+BkGjzRe3Q2A9Y4dttLy3ikQUEAgUMZr5+Myx6RTMyvZ0Kf32wVfXF7xoGraZtmLOftoEMRzoL7vWp5nnmo+E0/ieDe/8yx8tfXUTqCbyw5UK61qTAzJGiigt0OBWTpNlGlXkgiSavyK2CyIObdkQ1m566mks6C1E62bSSQBXKCQLtB5BE06xdvsIwbwaUjBDajrbjny1TRwliVYpuCDkCKuZcaycjsydy5cDF9HdsuOaj4Qui81EcNKGiy1Id8WuyRrjejnmXziNcfnayKj11A6JIGd2K1UIH2hlJd9z0atv+wrwudbUtWopCGE/t/cAAJPDj6NoAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAADHevlXTfHInaLE3ExyqYIcICy4LYLlL14XRRXXPpJi8AwZGb+UhFzL/7K26csOb57yM5bvF9xJrLEObOkAAAAAFSlNamSkhBk0k6HFg2jh8fDW13bySu4HkH6hAQQVEjQbd9uHXZaGT2cvhRs7reawctIXtX1s3kTqM9YV+/wCpxJrndgN4IFTxep3s6kO0ROug7bEsbx0xxuDkqEvwUusFCQAFAmDqAAAJAAkDqGEAAAAAAAALBAYIBQMKDGDjFgAAAAAABgoCAwIMaW52b2ljZS0yMDQ4BwIBBAwCAAAAiBMAAAAAAAA="
+	web3BatchTransferVector         = "ATUAuE8Wz2YAKUKpdnvXrhRDGgxUjbjj/BzmfykKFxqPxbNTal3LH6/KhFZ1oZgIZ+eRbwXb1uSAut93HnaElQUBAAEHQwRr/kCSs+lJlOraFdzCDYqqB7ZY/TlU644O+4vcpd4Zf2sj4WyFMsaryDj6zV6nib4MdrKSAzQDm/qLPTaNYUUIoHqpQXB/PrLblMiJeoCywRl0drbeITrCc999hsT/lHXGzw7aNAV1KNhpR4HsySrWOdfivSd2HtPvdJJL6wfpLrYFT+m8aCobzzt1n2WrOKTPvYHE0fM0LkzJze2LC/pINBR/bmkMNpPv9hM2BGQDzYrioU8xs8QHNYVpI5VlAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAADEmud2A3ggVPF6nezqQ7RE66DtsSxvHTHG4OSoS/BS6wUGAgAEDAIAAADoAwAAAAAAAAYCAAUMAgAAANAHAAAAAAAABgIAAQwCAAAAuAsAAAAAAAAGAgACDAIAAACgDwAAAAAAAAYCAAMMAgAAAIgTAAAAAAAA"
+)
+
+func newSeedAccount(b byte) *account.Account {
+	return account.NewAccountBySecret(bytes.Repeat([]byte{b}, 32))
+}
+
+func checkWeb3Vector(t *testing.T, name string, tx *transaction.Transaction, signers []*account.Account, vector string) {
+	t.Helper()
+	expect, err := base64.StdEncoding.DecodeString(vector)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Sign(signers); err != nil {
+		t.Fatal(err)
+	}
+	wireTx, err := tx.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(wireTx, expect) {
+		t.Fatalf("%s is not equal to web3.js,got=%s", name, base64.StdEncoding.EncodeToString(wireTx))
+	}
+}
+
+func Test_Web3MultiInstructionVectors(t *testing.T) {
+	var (
+		payer, owner, cosigner, funder = newSeedAccount(11), newSeedAccount(12), newSeedAccount(13), newSeedAccount(14)
+		source, destination            = newSeedAccount(21).GetPublicKey(), newSeedAccount(22).GetPublicKey()
+		mint, recipient                = newSeedAccount(23).GetPublicKey(), newSeedAccount(24).GetPublicKey()
+	)
+	limit, err := computebudget.NewSetComputeUnitLimit(60000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	price, err := computebudget.NewSetComputeUnitPrice(25000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	transferChecked, err := token.NewTransferChecked(token.TransferCheckedParams{
+		Source:      source,
+		Mint:        mint,
+		Destination: destination,
+		Owner:       owner.GetPublicKey(),
+		Amount:      1500000,
+		Decimals:    6,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	memoIns, err := memo.NewMemo(memo.MemoParams{
+		Memo:    "invoice-2048",
+		Signers: []account.PublicKey{owner.GetPublicKey(), cosigner.GetPublicKey()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	transfer, err := transaction.NewTransfer(transaction.TransferParams{
+		From:   funder.GetPublicKey(),
+		To:     recipient,
+		Amount: big.NewInt(5000),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := transaction.NewTransaction("EETubP5AKHgjPAhzPAFcb8BAY1hMH639CWCFTqi3hq1k")
+	tx.FeePayer = payer.GetPublicKey()
+	for _, ins := range []transaction.ITransactionInstruction{limit, price, transferChecked, memoIns, transfer} {
+		tx.SetInstructions(ins)
+	}
+	checkWeb3Vector(t, "token transfer with memo", tx, []*account.Account{payer, owner, cosigner, funder}, web3TokenTransferWithMemoVector)
+
+	batchPayer := newSeedAccount(31)
+	batch := transaction.NewTransaction("EETubP5AKHgjPAhzPAFcb8BAY1hMH639CWCFTqi3hq1k")
+	batch.FeePayer = batchPayer.GetPublicKey()
+	for i := 0; i < 5; i++ {
+		ins, err := transaction.NewTransfer(transaction.TransferParams{
+			From:   batchPayer.GetPublicKey(),
+			To:     newSeedAccount(byte(40 + i)).GetPublicKey(),
+			Amount: big.NewInt(int64(1000 * (i + 1))),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		batch.SetInstructions(ins)
+	}
+	checkWeb3Vector(t, "batch transfer", batch, []*account.Account{batchPayer}, web3BatchTransferVector)
+}
+
+func Test_CompileDeserializedMessage(t *testing.T) {
+	account1, _ := newTestAccounts()
+	foreign := newForeignMessage(account1)
+	decoded, err := transaction.DeserializeTransaction(newForeignWireTx(foreign, []*account.Account{account1}, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 从wire格式还原的交易不重新排序
+	message, err := decoded.CompileMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(message.Serialize(), foreign.Serialize()) {
+		t.Fatal("deserialized message should not be recompiled")
+	}
+	// 修改指令后按web3.js的顺序重新编译
+	decoded.SetInstructions(decoded.Instructions[0])
+	message, err = decoded.CompileMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []account.PublicKey{account1.GetPublicKey(), newTestPublicKey(2), newTestPublicKey(5), newTestPublicKey(9)}
+	if len(message.AccountKeys) != len(expected) {
+		t.Fatalf("account keys length error,got=%d", len(message.AccountKeys))
+	}
+	for i, k := range expected {
+		if message.AccountKeys[i] != k {
+			t.Fatalf("account key [%d] error,got=%s", i, message.AccountKeys[i].String())
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/JFJun/solana-go/account"
+	"github.com/JFJun/solana-go/addresslookuptable"
+	"github.com/JFJun/solana-go/computebudget"
+	"github.com/JFJun/solana-go/ed25519program"
+	"github.com/JFJun/solana-go/secp256k1program"
+	"github.com/JFJun/solana-go/systemprogram"
+	"github.com/JFJun/solana-go/token"
+	"github.com/JFJun/solana-go/transaction"
+)
+
+func Test_ComputeBudgetInstructions(t *testing.T) {
+	ins, err := computebudget.NewSetComputeUnitLimit(300000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "set compute unit limit", ins, computebudget.ProgramId, nil, "02e0930400")
+	ins, err = computebudget.NewSetComputeUnitPrice(1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "set compute unit price", ins, computebudget.ProgramId, nil, "03e803000000000000")
+	ins, err = computebudget.NewRequestHeapFrame(64 * 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "request heap frame", ins, computebudget.ProgramId, nil, "0100000100")
+	ins, err = computebudget.NewSetLoadedAccountsDataSizeLimit(65536)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "set loaded accounts data size limit", ins, computebudget.ProgramId, nil, "0400000100")
+	if _, err := computebudget.NewRequestHeapFrame(33 * 1000); err == nil {
+		t.Fatal("heap frame not multiple of 1024 should fail")
+	}
+	if _, err := computebudget.NewSetComputeUnitLimit(computebudget.MaxComputeUnitLimit + 1); err == nil {
+		t.Fatal("compute unit limit too large should fail")
+	}
+}
+
+func Test_SetComputeBudget(t *testing.T) {
+	tx := newSignedTransferTx(t)
+	params := computebudget.Params{ComputeUnitLimit: 300, ComputeUnitPrice: 50000}
+	if err := computebudget.SetComputeBudget(tx, params); err != nil {
+		t.Fatal(err)
+	}
+	// 重复设置时替换，不会重复添加
+	if err := computebudget.SetComputeBudget(tx, params); err != nil {
+		t.Fatal(err)
+	}
+	if len(tx.ComputeBudgetInstructions) != 2 || len(tx.Instructions) != 1 {
+		t.Fatal("compute budget instructions should be set as transaction option")
+	}
+	// 编译时放在最前，多次编译不会重复添加
+	for i := 0; i < 2; i++ {
+		if _, err := tx.CompileMessage(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(tx.Instructions) != 3 || tx.Instructions[0].GetProgramId() != computebudget.ProgramId ||
+		tx.Instructions[2].GetProgramId() != systemprogram.ProgramId {
+		t.Fatal("compute budget instructions should be prepended")
+	}
+	parsed, err := computebudget.ParseParams(tx.Instructions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *parsed != params {
+		t.Fatalf("parse params error,got=%+v", *parsed)
+	}
+	// 5000 + ceil(50000*300/1000000)=5000+15
+	fee, err := computebudget.CalculateFee(tx, computebudget.DefaultLamportsPerSignature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fee != 5015 {
+		t.Fatalf("fee error,got=%d", fee)
+	}
+}
+
+func Test_CalculateFeeDefaultLimit(t *testing.T) {
+	tx, account1, _ := newTwoSignerTx(t)
+	if err := computebudget.SetComputeBudget(tx, computebudget.Params{ComputeUnitPrice: 1000000}); err != nil {
+		t.Fatal(err)
+	}
+	// 两个签名，两条转账和一条compute budget指令都是builtin，各按3000计算：2*5000 + 9000
+	fee, err := computebudget.CalculateFee(tx, computebudget.DefaultLamportsPerSignature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fee != 19000 {
+		t.Fatalf("fee error,got=%d", fee)
+	}
+	// 与durable nonce同时使用时AdvanceNonceAccount在最前
+	nonceInfo, err := transaction.NewNonceInformation(newTestPublicKey(10), account1.GetPublicKey(), testNonceValue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx.NonceInfo = nonceInfo
+	message, err := tx.CompileMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if message.AccountKeys[message.Instructions[0].ProgramIdIndex] != systemprogram.ProgramId ||
+		message.AccountKeys[message.Instructions[1].ProgramIdIndex] != computebudget.ProgramId {
+		t.Fatal("advance nonce account should be the first instruction")
+	}
+	// 编译后再次设置，AdvanceNonceAccount不会重复
+	if err := computebudget.SetComputeBudget(tx, computebudget.Params{ComputeUnitPrice: 2000000}); err != nil {
+		t.Fatal(err)
+	}
+	message, err = tx.CompileMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(message.Instructions) != 4 || message.AccountKeys[message.Instructions[0].ProgramIdIndex] != systemprogram.ProgramId ||
+		message.AccountKeys[message.Instructions[1].ProgramIdIndex] != computebudget.ProgramId {
+		t.Fatal("reset compute budget on nonce transaction error")
+	}
+
+	// 非builtin程序的指令按200000计算：5000 + 1*(200000+3000)
+	owner := newTestPublicKey(1)
+	transfer, err := token.NewTransfer(token.TransferParams{Source: newTestPublicKey(2), Destination: newTestPublicKey(3), Owner: owner, Amount: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokenTx := transaction.NewTransaction("EETubP5AKHgjPAhzPAFcb8BAY1hMH639CWCFTqi3hq1k")
+	tokenTx.FeePayer = owner
+	tokenTx.SetInstructions(transfer)
+	if err := computebudget.SetComputeBudget(tokenTx, computebudget.Params{ComputeUnitPrice: 1000000}); err != nil {
+		t.Fatal(err)
+	}
+	fee, err = computebudget.CalculateFee(tokenTx, computebudget.DefaultLamportsPerSignature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fee != 208000 {
+		t.Fatalf("token transfer fee error,got=%d", fee)
+	}
+	if computebudget.PriorityFee(^uint64(0), computebudget.MaxComputeUnitLimit) != ^uint64(0) {
+		t.Fatal("priority fee overflow error")
+	}
+}
+
+func Test_CalculateFeePrecompiles(t *testing.T) {
+	owner, _ := newTestAccounts()
+	freeze, err := addresslookuptable.NewFreezeLookupTable(addresslookuptable.FreezeLookupTableParams{
+		LookupTable: newTestPublicKey(2),
+		Authority:   owner.GetPublicKey(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify, err := ed25519program.NewInstructionWithAccount(ed25519program.InstructionWithAccountParams{
+		Account: owner,
+		Message: []byte("hello solana"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 只有签名数量会影响手续费
+	verify2, err := transaction.NewTransactionInstruction(secp256k1program.ProgramId, []*transaction.AccountMeta{}, []byte{2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := transaction.NewTransaction("EETubP5AKHgjPAhzPAFcb8BAY1hMH639CWCFTqi3hq1k")
+	tx.SetInstructions(freeze)
+	tx.SetInstructions(verify)
+	tx.SetInstructions(verify2)
+	if err := tx.Sign([]*account.Account{owner}); err != nil {
+		t.Fatal(err)
+	}
+	if err := computebudget.SetComputeBudget(tx, computebudget.Params{ComputeUnitPrice: 1000000}); err != nil {
+		t.Fatal(err)
+	}
+	// (1+1+2)*5000，查找表、precompile和compute budget指令都是builtin：4*3000
+	fee, err := computebudget.CalculateFee(tx, computebudget.DefaultLamportsPerSignature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fee != 32000 {
+		t.Fatalf("fee error,got=%d", fee)
+	}
+}
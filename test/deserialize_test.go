@@ -0,0 +1,225 @@
+package test
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/JFJun/solana-go/account"
+	"github.com/JFJun/solana-go/transaction"
+)
+
+func newTestAccounts() (*account.Account, *account.Account) {
+	a1 := []byte{
+		131, 158, 144, 122, 59, 1, 90, 107, 206, 5, 55,
+		58, 64, 222, 94, 76, 173, 0, 9, 240, 27, 122,
+		37, 146, 137, 94, 111, 197, 158, 179, 28, 222,
+	}
+	a2 := []byte{190, 250, 118, 57, 122, 206, 17, 161, 253, 9, 177,
+		162, 79, 20, 93, 163, 121, 39, 77, 196, 160, 227,
+		126, 135, 49, 231, 170, 6, 55, 16, 217, 153}
+	return account.NewAccountBySecret(a1), account.NewAccountBySecret(a2)
+}
+
+func newSignedTransferTx(t *testing.T) *transaction.Transaction {
+	account1, account2 := newTestAccounts()
+	transfer, err := transaction.NewTransfer(transaction.TransferParams{
+		From:   account1.GetPublicKey(),
+		To:     account2.GetPublicKey(),
+		Amount: big.NewInt(100000000),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := transaction.NewTransaction("EETubP5AKHgjPAhzPAFcb8BAY1hMH639CWCFTqi3hq1k")
+	tx.SetInstructions(transfer)
+	if err := tx.Sign([]*account.Account{account1}); err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+func Test_DeserializeMessage(t *testing.T) {
+	tx := newSignedTransferTx(t)
+	message, err := tx.CompileMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := message.Serialize()
+	decoded, err := transaction.DeserializeMessage(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(decoded.Serialize(), data) {
+		t.Fatal("message round trip error")
+	}
+	if decoded.RecentBlockHash != "EETubP5AKHgjPAhzPAFcb8BAY1hMH639CWCFTqi3hq1k" {
+		t.Fatalf("recent block hash error,got=%s", decoded.RecentBlockHash)
+	}
+	if *decoded.Header != *message.Header || len(decoded.AccountKeys) != 3 {
+		t.Fatal("message header error")
+	}
+	if !decoded.IsAccountSigner(0) || !decoded.IsAccountWritable(0) || !decoded.IsAccountWritable(1) || decoded.IsAccountWritable(2) {
+		t.Fatal("message account flags error")
+	}
+	// 截断的数据必须报错
+	for i := 0; i < len(data); i++ {
+		if _, err := transaction.DeserializeMessage(data[:i]); err == nil {
+			t.Fatalf("truncated message [%d] should fail", i)
+		}
+	}
+}
+
+func Test_DeserializeTransaction(t *testing.T) {
+	tx := newSignedTransferTx(t)
+	wireTx, err := tx.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := transaction.DeserializeTransaction(wireTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.Signatures) != 1 || !bytes.Equal(decoded.Signatures[0].Signature, tx.Signatures[0].Signature) {
+		t.Fatal("signature error")
+	}
+	if !decoded.Signatures[0].PublicKey.Equals(tx.Signatures[0].PublicKey) {
+		t.Fatal("signer error")
+	}
+	if len(decoded.Instructions) != 1 || !bytes.Equal(decoded.Instructions[0].GetData(), tx.Instructions[0].GetData()) {
+		t.Fatal("instruction error")
+	}
+	keys := decoded.Instructions[0].GetKeys()
+	if !keys[0].IsSigner || !keys[0].IsWriteable || keys[1].IsSigner || !keys[1].IsWriteable {
+		t.Fatal("instruction keys error")
+	}
+	reWireTx, err := decoded.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(reWireTx, wireTx) {
+		t.Fatal("transaction round trip error")
+	}
+}
+
+func Test_DecodeLength(t *testing.T) {
+	// 指令数据长度>=128时shortvec占两个字节
+	tx := newSignedTransferTx(t)
+	message, err := tx.CompileMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, length := range []int{0, 127, 128, 300, 16383, 16384} {
+		message.Instructions[0].Data = bytes.Repeat([]byte{2}, length)
+		decoded, err := transaction.DeserializeMessage(message.Serialize())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(decoded.Instructions[0].Data, message.Instructions[0].Data) {
+			t.Fatalf("instruction data [%d] round trip error", length)
+		}
+	}
+}
+
+/*
+不是web3.js顺序的message（例如Rust SDK构造的交易）：pk5与pk2都是可写非签名账户，web3.js会把pk2排在前面
+signers为签名账户，第一个为手续费支付者
+*/
+func newForeignMessage(signers ...*account.Account) *transaction.Message {
+	var keys []account.PublicKey
+	for _, s := range signers {
+		keys = append(keys, s.GetPublicKey())
+	}
+	keys = append(keys, newTestPublicKey(5), newTestPublicKey(2), newTestPublicKey(9))
+	n := len(signers)
+	return &transaction.Message{
+		Header: &transaction.MessageHeader{
+			NumRequiredSignatures:       n,
+			NumReadonlyUnsignedAccounts: 1,
+		},
+		AccountKeys:     keys,
+		RecentBlockHash: "EETubP5AKHgjPAhzPAFcb8BAY1hMH639CWCFTqi3hq1k",
+		Instructions: []*transaction.CompiledInstruction{
+			{ProgramIdIndex: n + 2, Accounts: []int{0, n, n + 1}, Data: []byte{1, 2, 3}},
+		},
+	}
+}
+
+/*
+前numSigned个签名者签名，其余位置填充0
+*/
+func newForeignWireTx(message *transaction.Message, signers []*account.Account, numSigned int) []byte {
+	data := message.Serialize()
+	wireTx := []byte{byte(len(signers))}
+	for i, s := range signers {
+		if i < numSigned {
+			wireTx = append(wireTx, s.Sign(data)...)
+		} else {
+			wireTx = append(wireTx, make([]byte, 64)...)
+		}
+	}
+	return append(wireTx, data...)
+}
+
+func Test_DeserializeForeignTransaction(t *testing.T) {
+	account1, _ := newTestAccounts()
+	wireTx := newForeignWireTx(newForeignMessage(account1), []*account.Account{account1}, 1)
+	decoded, err := transaction.DeserializeTransaction(wireTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reWireTx, err := decoded.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(reWireTx, wireTx) {
+		t.Fatal("foreign transaction round trip error")
+	}
+	// 修改指令后重新编译
+	decoded.SetInstructions(decoded.Instructions[0])
+	message, err := decoded.SerializeMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(message, wireTx[65:]) {
+		t.Fatal("modified transaction should be recompiled")
+	}
+}
+
+func Test_DecodeLengthNotCanonical(t *testing.T) {
+	tx := newSignedTransferTx(t)
+	wireTx, err := tx.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 签名数量1编码为0x81 0x00，runtime会拒绝
+	alias := append([]byte{0x81, 0x00}, wireTx[1:]...)
+	if _, err := transaction.DeserializeTransaction(alias); err == nil {
+		t.Fatal("non-canonical shortvec should fail")
+	}
+	// 0x81 0x80 0x04 = 65537，超过u16
+	overflow := append([]byte{0x81, 0x80, 0x04}, wireTx[1:]...)
+	if _, err := transaction.DeserializeTransaction(overflow); err == nil {
+		t.Fatal("shortvec overflow should fail")
+	}
+}
+
+func Test_PopulateTransactionInvalidIndex(t *testing.T) {
+	account1, _ := newTestAccounts()
+	signatures := [][]byte{make([]byte, 64)}
+	message := newForeignMessage(account1)
+	message.Instructions[0].ProgramIdIndex = 10
+	if _, err := transaction.PopulateTransaction(message, signatures); err == nil {
+		t.Fatal("program id index out of range should fail")
+	}
+	message = newForeignMessage(account1)
+	message.Instructions[0].Accounts = []int{0, -1}
+	if _, err := transaction.PopulateTransaction(message, signatures); err == nil {
+		t.Fatal("account index out of range should fail")
+	}
+	message = newForeignMessage(account1)
+	message.Header = nil
+	if _, err := transaction.PopulateTransaction(message, signatures); err == nil {
+		t.Fatal("message without header should fail")
+	}
+}
@@ -0,0 +1,88 @@
+package test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/JFJun/solana-go/account"
+	"github.com/JFJun/solana-go/transaction"
+)
+
+func Test_FeePayer(t *testing.T) {
+	user, to := newTestAccounts()
+	gas, err := account.NewAccount()
+	if err != nil {
+		t.Fatal(err)
+	}
+	transfer, err := transaction.NewTransfer(transaction.TransferParams{
+		From:   user.GetPublicKey(),
+		To:     to.GetPublicKey(),
+		Amount: big.NewInt(1000),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := transaction.NewTransaction("EETubP5AKHgjPAhzPAFcb8BAY1hMH639CWCFTqi3hq1k")
+	tx.SetInstructions(transfer)
+	tx.FeePayer = gas.GetPublicKey()
+	if err := tx.Sign([]*account.Account{user}); err == nil {
+		t.Fatal("sign without fee payer should fail")
+	}
+	// 签名账户的顺序不影响手续费支付者
+	if err := tx.Sign([]*account.Account{user, gas}); err != nil {
+		t.Fatal(err)
+	}
+	message, err := tx.CompileMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !message.AccountKeys[0].Equals(gas.GetPublicKey()) || !message.IsAccountWritable(0) || !message.IsAccountSigner(0) {
+		t.Fatal("fee payer should be the first writable signer")
+	}
+	if message.Header.NumRequiredSignatures != 2 || !message.AccountKeys[1].Equals(user.GetPublicKey()) {
+		t.Fatal("user should be the second signer")
+	}
+	if !tx.Signatures[0].PublicKey.Equals(gas.GetPublicKey()) {
+		t.Fatal("fee payer signature should be the first")
+	}
+	wireTx, err := tx.SerializeWithConfig(transaction.SerializeConfig{
+		RequireAllSignatures: true,
+		VerifySignatures:     true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := transaction.DeserializeTransaction(wireTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.FeePayer.Equals(gas.GetPublicKey()) {
+		t.Fatal("decoded fee payer error")
+	}
+}
+
+func Test_FeePayerFromTwoTransfers(t *testing.T) {
+	tx, account1, account2 := newTwoSignerTx(t)
+	// 不设置FeePayer时使用第一个签名账户
+	if err := tx.Sign([]*account.Account{account2, account1}); err != nil {
+		t.Fatal(err)
+	}
+	message, err := tx.CompileMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !message.AccountKeys[0].Equals(account2.GetPublicKey()) {
+		t.Fatal("first sign account should pay the fee")
+	}
+	tx.FeePayer = account1.GetPublicKey()
+	if err := tx.Sign([]*account.Account{account2, account1}); err != nil {
+		t.Fatal(err)
+	}
+	message, err = tx.CompileMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !message.AccountKeys[0].Equals(account1.GetPublicKey()) {
+		t.Fatal("explicit fee payer should pay the fee")
+	}
+}
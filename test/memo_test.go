@@ -0,0 +1,120 @@
+package test
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/JFJun/solana-go/account"
+	"github.com/JFJun/solana-go/memo"
+	"github.com/JFJun/solana-go/rpc"
+	"github.com/JFJun/solana-go/transaction"
+)
+
+func Test_NewMemo(t *testing.T) {
+	signer := newTestPublicKey(1)
+	ins, err := memo.NewMemo(memo.MemoParams{Memo: "deposit:12345", Signers: []account.PublicKey{signer}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "memo", ins, memo.ProgramId, []expectMeta{{signer, true, false}}, "6465706f7369743a3132333435")
+
+	ins, err = memo.NewMemo(memo.MemoParams{Memo: "v1", ProgramId: memo.ProgramIdV1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ins.GetProgramId().Equals(memo.ProgramIdV1) || len(ins.GetKeys()) != 0 {
+		t.Fatal("memo v1 error")
+	}
+	if _, err := memo.NewMemo(memo.MemoParams{Memo: "v1", ProgramId: memo.ProgramIdV1, Signers: []account.PublicKey{signer}}); err == nil {
+		t.Fatal("memo v1 with signers should fail")
+	}
+	if _, err := memo.NewMemo(memo.MemoParams{Memo: string([]byte{0xff, 0xfe})}); err == nil {
+		t.Fatal("invalid utf8 memo should fail")
+	}
+	if _, err := memo.NewMemo(memo.MemoParams{Memo: "x", ProgramId: newTestPublicKey(2)}); err == nil {
+		t.Fatal("other program id should fail")
+	}
+}
+
+func Test_ExtractMemos(t *testing.T) {
+	tx := newSignedTransferTx(t)
+	account1, _ := newTestAccounts()
+	if err := memo.AddMemo(tx, memo.MemoParams{Memo: "tag-1", Signers: []account.PublicKey{account1.GetPublicKey()}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := memo.AddMemo(tx, memo.MemoParams{Memo: "tag-2", ProgramId: memo.ProgramIdV1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Sign([]*account.Account{account1}); err != nil {
+		t.Fatal(err)
+	}
+	wireTx, err := tx.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	memos, err := memo.ExtractMemosFromWireTransaction(wireTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(memos) != 2 || memos[0] != "tag-1" || memos[1] != "tag-2" {
+		t.Fatalf("extract memos error,got=%v", memos)
+	}
+	decoded, err := transaction.DeserializeTransaction(wireTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if memos := memo.ExtractMemos(decoded); len(memos) != 2 || memos[0] != "tag-1" {
+		t.Fatalf("extract memos from transaction error,got=%v", memos)
+	}
+
+	server := newMockRpcServer(t, map[string]string{
+		"getTransaction": `{"slot":100,"blockTime":1600000000,"transaction":["` + base64.StdEncoding.EncodeToString(wireTx) +
+			`","base64"],"meta":{"err":null,"fee":5000,"preBalances":[1,2],"postBalances":[1,2],"logMessages":[]},"version":"legacy"}`,
+	})
+	defer server.Close()
+	result, err := rpc.New(server.URL, "", "").GetTransaction("sig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Slot != 100 || *result.BlockTime != 1600000000 || result.Meta.Err != nil || result.Meta.Fee != 5000 {
+		t.Fatalf("get transaction error,got=%+v", result)
+	}
+	memos, err = memo.ExtractMemosFromRpcTransaction(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(memos) != 2 || memos[1] != "tag-2" {
+		t.Fatalf("extract memos from rpc error,got=%v", memos)
+	}
+}
+
+func Test_ExtractMemosV0(t *testing.T) {
+	tx, payer, _ := newV0TransferTx(t)
+	if err := memo.AddMemo(tx, memo.MemoParams{Memo: "v0-tag"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Sign([]*account.Account{payer}); err != nil {
+		t.Fatal(err)
+	}
+	wireTx, err := tx.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 不需要地址查找表
+	memos, err := memo.ExtractMemosFromWireTransaction(wireTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(memos) != 1 || memos[0] != "v0-tag" {
+		t.Fatalf("extract v0 memos error,got=%v", memos)
+	}
+}
+
+func Test_GetTransactionNotFound(t *testing.T) {
+	server := newMockRpcServer(t, map[string]string{"getTransaction": `null`})
+	defer server.Close()
+	result, err := rpc.New(server.URL, "", "").GetTransaction("sig")
+	if err != nil || result != nil {
+		t.Fatal("missing transaction should return nil")
+	}
+}
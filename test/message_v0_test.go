@@ -0,0 +1,112 @@
+package test
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"math/big"
+	"testing"
+
+	"github.com/JFJun/solana-go/account"
+	"github.com/JFJun/solana-go/transaction"
+)
+
+func newV0TransferTx(t *testing.T) (*transaction.Transaction, *account.Account, *transaction.AddressLookupTableAccount) {
+	payer, _ := newTestAccounts()
+	table := &transaction.AddressLookupTableAccount{
+		Key: account.MustPublicKeyFromBase58("BHUNqtk5Vv6vfQTxpPjqWo2v8GPZJbqBonCaqhhK1Hub"),
+	}
+	tx := transaction.NewTransaction("EETubP5AKHgjPAhzPAFcb8BAY1hMH639CWCFTqi3hq1k")
+	for i := 0; i < 3; i++ {
+		to, err := account.NewAccount()
+		if err != nil {
+			t.Fatal(err)
+		}
+		table.Addresses = append(table.Addresses, to.GetPublicKey())
+		transfer, err := transaction.NewTransfer(transaction.TransferParams{
+			From:   payer.GetPublicKey(),
+			To:     to.GetPublicKey(),
+			Amount: big.NewInt(int64(1000 + i)),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		tx.SetInstructions(transfer)
+	}
+	tx.Version = transaction.MessageVersion0
+	tx.AddressLookupTables = []*transaction.AddressLookupTableAccount{table}
+	return tx, payer, table
+}
+
+func Test_CompileMessageV0(t *testing.T) {
+	tx, payer, table := newV0TransferTx(t)
+	message, err := tx.CompileMessageV0(tx.AddressLookupTables)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// payer + system program
+	if len(message.StaticAccountKeys) != 2 || !message.StaticAccountKeys[0].Equals(payer.GetPublicKey()) {
+		t.Fatalf("static account keys error,got=%v", message.StaticAccountKeys)
+	}
+	if len(message.AddressTableLookups) != 1 || len(message.AddressTableLookups[0].WritableIndexes) != 3 {
+		t.Fatal("address table lookups error")
+	}
+	keys, err := message.ResolveAccountKeys([]*transaction.AddressLookupTableAccount{table})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, ins := range message.Instructions {
+		to := keys[ins.Accounts[1]]
+		if !to.Equals(tx.Instructions[i].GetKeys()[1].PubKey) || !message.IsAccountWritable(ins.Accounts[1]) {
+			t.Fatalf("instruction %d recipient error", i)
+		}
+	}
+	data := message.Serialize()
+	if data[0] != 0x80 {
+		t.Fatalf("version prefix error,got=%d", data[0])
+	}
+	decoded, err := transaction.DeserializeMessageV0(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(decoded.Serialize(), data) {
+		t.Fatal("message v0 round trip error")
+	}
+	if _, err := transaction.DeserializeMessage(data); err == nil {
+		t.Fatal("legacy deserialize should reject v0 message")
+	}
+}
+
+func Test_SignTransactionV0(t *testing.T) {
+	tx, payer, table := newV0TransferTx(t)
+	if err := tx.Sign([]*account.Account{payer}); err != nil {
+		t.Fatal(err)
+	}
+	wireTx, err := tx.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	message, err := tx.CompileMessageV0(tx.AddressLookupTables)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ed25519.Verify(payer.GetPublicKey().Bytes(), message.Serialize(), tx.Signatures[0].Signature) {
+		t.Fatal("v0 signature verify error")
+	}
+	if _, err := transaction.DeserializeTransaction(wireTx); err == nil {
+		t.Fatal("deserialize v0 transaction without lookup table should fail")
+	}
+	decoded, err := transaction.DeserializeTransaction(wireTx, table)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Version != transaction.MessageVersion0 || len(decoded.Instructions) != 3 {
+		t.Fatal("decoded v0 transaction error")
+	}
+	reWireTx, err := decoded.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(reWireTx, wireTx) {
+		t.Fatal("v0 transaction round trip error")
+	}
+}
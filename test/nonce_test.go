@@ -0,0 +1,156 @@
+package test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"math/big"
+	"testing"
+
+	"github.com/JFJun/solana-go/account"
+	"github.com/JFJun/solana-go/rpc"
+	"github.com/JFJun/solana-go/systemprogram"
+	"github.com/JFJun/solana-go/transaction"
+	"github.com/btcsuite/btcutil/base58"
+)
+
+const testNonceValue = "EETubP5AKHgjPAhzPAFcb8BAY1hMH639CWCFTqi3hq1k"
+
+func newNonceAccountData(authority account.PublicKey, state uint32) []byte {
+	data := make([]byte, systemprogram.NonceAccountLength)
+	binary.LittleEndian.PutUint32(data[0:4], systemprogram.NonceVersionCurrent)
+	binary.LittleEndian.PutUint32(data[4:8], state)
+	copy(data[8:40], authority.Bytes())
+	copy(data[40:72], base58.Decode(testNonceValue))
+	binary.LittleEndian.PutUint64(data[72:80], 5000)
+	return data
+}
+
+func Test_DeserializeNonceAccount(t *testing.T) {
+	authority := newTestPublicKey(9)
+	na, err := systemprogram.DeserializeNonceAccount(newNonceAccountData(authority, systemprogram.NonceStateInitialized))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !na.IsInitialized() || !na.Authority.Equals(authority) || na.Nonce != testNonceValue || na.LamportsPerSignature != 5000 {
+		t.Fatalf("nonce account error,got=%+v", na)
+	}
+	if _, err := systemprogram.DeserializeNonceAccount(make([]byte, 79)); err == nil {
+		t.Fatal("short nonce account data should fail")
+	}
+}
+
+func Test_GetNonceAccount(t *testing.T) {
+	authority := newTestPublicKey(9)
+	data := base64.StdEncoding.EncodeToString(newNonceAccountData(authority, systemprogram.NonceStateInitialized))
+	server := newMockRpcServer(t, map[string]string{
+		"getAccountInfo": `{"context":{"slot":1},"value":{"data":["` + data + `","base64"],"executable":false,` +
+			`"lamports":1447680,"owner":"11111111111111111111111111111111","rentEpoch":0}}`,
+	})
+	defer server.Close()
+	client := rpc.New(server.URL, "", "")
+	na, err := systemprogram.GetNonceAccount(client, newTestPublicKey(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if na.Nonce != testNonceValue || !na.Authority.Equals(authority) {
+		t.Fatalf("nonce account error,got=%+v", na)
+	}
+
+	uninitialized := base64.StdEncoding.EncodeToString(newNonceAccountData(authority, systemprogram.NonceStateUninitialized))
+	server2 := newMockRpcServer(t, map[string]string{
+		"getAccountInfo": `{"context":{"slot":1},"value":{"data":["` + uninitialized + `","base64"],"executable":false,` +
+			`"lamports":1447680,"owner":"11111111111111111111111111111111","rentEpoch":0}}`,
+	})
+	defer server2.Close()
+	if _, err := systemprogram.GetNonceAccount(rpc.New(server2.URL, "", ""), newTestPublicKey(10)); err == nil {
+		t.Fatal("uninitialized nonce account should fail")
+	}
+}
+
+func Test_CreateNonceAccount(t *testing.T) {
+	from, nonce := newTestAccounts()
+	ins, err := transaction.NewCreateNonceAccount(transaction.CreateNonceAccountParams{
+		From:       from.GetPublicKey(),
+		Nonce:      nonce.GetPublicKey(),
+		Authorized: from.GetPublicKey(),
+		Lamports:   1447680,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ins) != 2 {
+		t.Fatalf("create nonce account instruction length error,got=%d", len(ins))
+	}
+	create := ins[0].GetData()
+	if binary.LittleEndian.Uint32(create[0:4]) != systemprogram.InstructionCreateAccount ||
+		binary.LittleEndian.Uint64(create[12:20]) != systemprogram.NonceAccountLength {
+		t.Fatal("create account data error")
+	}
+	if binary.LittleEndian.Uint32(ins[1].GetData()[0:4]) != systemprogram.InstructionInitializeNonceAccount {
+		t.Fatal("initialize nonce account data error")
+	}
+	tx := transaction.NewTransaction(testNonceValue)
+	for _, in := range ins {
+		tx.SetInstructions(in)
+	}
+	if err := tx.Sign([]*account.Account{from, nonce}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.SerializeWithConfig(transaction.SerializeConfig{RequireAllSignatures: true, VerifySignatures: true}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_NonceTransaction(t *testing.T) {
+	authority, to := newTestAccounts()
+	nonceAccount := newTestPublicKey(10)
+	nonceInfo, err := transaction.NewNonceInformation(nonceAccount, authority.GetPublicKey(), testNonceValue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	transfer, err := transaction.NewTransfer(transaction.TransferParams{
+		From:   authority.GetPublicKey(),
+		To:     to.GetPublicKey(),
+		Amount: big.NewInt(1000),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := transaction.NewNonceTransaction(nonceInfo)
+	tx.SetInstructions(transfer)
+	if err := tx.Sign([]*account.Account{authority}); err != nil {
+		t.Fatal(err)
+	}
+	message, err := tx.CompileMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if message.RecentBlockHash != testNonceValue {
+		t.Fatalf("recent block hash should be the nonce,got=%s", message.RecentBlockHash)
+	}
+	if len(message.Instructions) != 2 {
+		t.Fatalf("instruction length error,got=%d", len(message.Instructions))
+	}
+	advance := message.Instructions[0]
+	if !message.AccountKeys[advance.ProgramIdIndex].Equals(systemprogram.ProgramId) ||
+		!bytes.Equal(advance.Data, []byte{4, 0, 0, 0}) {
+		t.Fatal("first instruction should be advance nonce account")
+	}
+	// nonce账户 | RecentBlockhashes sysvar | authority
+	if len(advance.Accounts) != 3 || !message.AccountKeys[advance.Accounts[0]].Equals(nonceAccount) ||
+		!message.IsAccountWritable(advance.Accounts[0]) || !message.IsAccountSigner(advance.Accounts[2]) {
+		t.Fatal("advance nonce account keys error")
+	}
+	// 多次编译不会重复插入advance指令
+	again, err := tx.CompileMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(again.Serialize(), message.Serialize()) {
+		t.Fatal("compile nonce message is not deterministic")
+	}
+	if _, err := transaction.NewNonceInformation(nonceAccount, authority.GetPublicKey(), ""); err == nil {
+		t.Fatal("empty nonce value should fail")
+	}
+}
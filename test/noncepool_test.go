@@ -0,0 +1,205 @@
+package test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/JFJun/solana-go/account"
+	"github.com/JFJun/solana-go/noncepool"
+	"github.com/JFJun/solana-go/rpc"
+	"github.com/JFJun/solana-go/systemprogram"
+	"github.com/JFJun/solana-go/transaction"
+	"github.com/btcsuite/btcutil/base58"
+)
+
+/*
+模拟链上nonce账户，所有nonce账户返回相同的nonce值，可以通过setNonce模拟nonce推进
+*/
+type mockNonceChain struct {
+	mu        sync.Mutex
+	authority account.PublicKey
+	nonce     string
+}
+
+func (c *mockNonceChain) setNonce(nonce string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.nonce = nonce
+}
+
+func (c *mockNonceChain) serve(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		buf := new(bytes.Buffer)
+		if _, err := buf.ReadFrom(r.Body); err != nil {
+			t.Error(err)
+			return
+		}
+		switch {
+		case strings.Contains(buf.String(), "getMinimumBalanceForRentExemption"):
+			w.Write([]byte(`{"jsonrpc":"2.0","result":1447680,"id":1}`))
+		case strings.Contains(buf.String(), "getAccountInfo"):
+			c.mu.Lock()
+			data := newNonceAccountData(c.authority, systemprogram.NonceStateInitialized)
+			copy(data[40:72], base58.Decode(c.nonce))
+			c.mu.Unlock()
+			w.Write([]byte(`{"jsonrpc":"2.0","result":{"context":{"slot":1},"value":{"data":["` +
+				base64.StdEncoding.EncodeToString(data) + `","base64"],"executable":false,"lamports":1447680,` +
+				`"owner":"11111111111111111111111111111111","rentEpoch":0}},"id":1}`))
+		default:
+			w.Write([]byte(`{"jsonrpc":"2.0","error":{"code":-32601,"message":"Method not found"},"id":1}`))
+		}
+	}))
+}
+
+func Test_NoncePoolProvision(t *testing.T) {
+	payer, authority := newTestAccounts()
+	chain := &mockNonceChain{authority: authority.GetPublicKey(), nonce: testNonceValue}
+	server := chain.serve(t)
+	defer server.Close()
+
+	pool := noncepool.New(rpc.New(server.URL, "", ""), authority.GetPublicKey())
+	txs, err := pool.Provision(payer, 3, testNonceValue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != 3 || pool.Stats()[noncepool.NonceStale] != 3 {
+		t.Fatal("provision nonce accounts error")
+	}
+	for _, tx := range txs {
+		if _, err := tx.SerializeWithConfig(transaction.SerializeConfig{RequireAllSignatures: true, VerifySignatures: true}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := pool.Reserve(); err != noncepool.ErrNoAvailableNonce {
+		t.Fatal("stale nonce should not be reserved")
+	}
+	if err := pool.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if pool.Stats()[noncepool.NonceAvailable] != 3 {
+		t.Fatal("refresh nonce accounts error")
+	}
+}
+
+func Test_NoncePoolLifecycle(t *testing.T) {
+	_, authority := newTestAccounts()
+	chain := &mockNonceChain{authority: authority.GetPublicKey(), nonce: testNonceValue}
+	server := chain.serve(t)
+	defer server.Close()
+
+	pool := noncepool.New(rpc.New(server.URL, "", ""), authority.GetPublicKey())
+	pool.Add(newTestPublicKey(10), newTestPublicKey(11))
+	if err := pool.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	r1, err := pool.Reserve()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r1.NonceInfo.Nonce != testNonceValue || !r1.NonceAccount.Equals(newTestPublicKey(10)) {
+		t.Fatal("reserve nonce error")
+	}
+	r2, err := pool.Reserve()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pool.Reserve(); err != noncepool.ErrNoAvailableNonce {
+		t.Fatal("pool should be exhausted")
+	}
+	if err := pool.Release(r2.NonceAccount); err != nil {
+		t.Fatal(err)
+	}
+	if err := pool.Release(r2.NonceAccount); err == nil {
+		t.Fatal("release available nonce should fail")
+	}
+	if err := pool.MarkConsumed(r1.NonceAccount); err != nil {
+		t.Fatal(err)
+	}
+	// 交易未上链，nonce值不变，保持consumed
+	if err := pool.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if status, _ := pool.Status(r1.NonceAccount); status != noncepool.NonceConsumed {
+		t.Fatalf("nonce status should be consumed,got=%s", status)
+	}
+	// 交易上链后nonce推进
+	advanced := "J3dxNj7nDRRqRRXuEMynDG57DkZK4jYRuv3Garmb1i99"
+	chain.setNonce(advanced)
+	if err := pool.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if status, _ := pool.Status(r1.NonceAccount); status != noncepool.NonceAvailable {
+		t.Fatalf("nonce status should be available,got=%s", status)
+	}
+	r3, err := pool.Reserve()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r3.NonceAccount.Equals(r1.NonceAccount) || r3.NonceInfo.Nonce != advanced {
+		t.Fatal("refreshed nonce value error")
+	}
+}
+
+func Test_NoncePoolAuthorityMismatch(t *testing.T) {
+	_, authority := newTestAccounts()
+	chain := &mockNonceChain{authority: newTestPublicKey(9), nonce: testNonceValue}
+	server := chain.serve(t)
+	defer server.Close()
+
+	pool := noncepool.New(rpc.New(server.URL, "", ""), authority.GetPublicKey())
+	pool.Add(newTestPublicKey(10))
+	if err := pool.Refresh(); err == nil {
+		t.Fatal("nonce account with other authority should fail")
+	}
+	if pool.Stats()[noncepool.NonceAvailable] != 0 {
+		t.Fatal("nonce account with other authority should not be available")
+	}
+}
+
+func Test_NoncePoolConcurrentReserve(t *testing.T) {
+	_, authority := newTestAccounts()
+	chain := &mockNonceChain{authority: authority.GetPublicKey(), nonce: testNonceValue}
+	server := chain.serve(t)
+	defer server.Close()
+
+	pool := noncepool.New(rpc.New(server.URL, "", ""), authority.GetPublicKey())
+	const size = 20
+	for i := 0; i < size; i++ {
+		pool.Add(newTestPublicKey(byte(100 + i)))
+	}
+	if err := pool.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		reserved = make(map[account.PublicKey]int)
+	)
+	for i := 0; i < size*2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r, err := pool.Reserve()
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			reserved[r.NonceAccount]++
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	if len(reserved) != size {
+		t.Fatalf("reserved nonce number error,got=%d", len(reserved))
+	}
+	for na, count := range reserved {
+		if count != 1 {
+			t.Fatalf("nonce account [%s] reserved %d times", na.String(), count)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package test
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"math/big"
+	"testing"
+
+	"github.com/JFJun/solana-go/account"
+	"github.com/JFJun/solana-go/transaction"
+)
+
+/*
+account1与account2互相转账，两个账户都需要签名
+*/
+func newTwoSignerTx(t *testing.T) (*transaction.Transaction, *account.Account, *account.Account) {
+	account1, account2 := newTestAccounts()
+	transfer, err := transaction.NewTransfer(transaction.TransferParams{
+		From:   account1.GetPublicKey(),
+		To:     account2.GetPublicKey(),
+		Amount: big.NewInt(100000000),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	transfer2, err := transaction.NewTransfer(transaction.TransferParams{
+		From:   account2.GetPublicKey(),
+		To:     account1.GetPublicKey(),
+		Amount: big.NewInt(123),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := transaction.NewTransaction("EETubP5AKHgjPAhzPAFcb8BAY1hMH639CWCFTqi3hq1k")
+	tx.SetInstructions(transfer)
+	tx.SetInstructions(transfer2)
+	return tx, account1, account2
+}
+
+func Test_PartialSign(t *testing.T) {
+	tx, account1, account2 := newTwoSignerTx(t)
+	if err := tx.PartialSign([]*account.Account{account1}); err != nil {
+		t.Fatal(err)
+	}
+	missing, err := tx.MissingSigners()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(missing) != 1 || !missing[0].Equals(account2.GetPublicKey()) {
+		t.Fatalf("missing signers error,got=%v", missing)
+	}
+	if _, err := tx.Serialize(); err == nil {
+		t.Fatal("serialize should fail when signatures are missing")
+	}
+
+	// 离线设备只拿到message数据
+	message, err := tx.SerializeMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.AddSignature(account2.GetPublicKey(), account2.Sign(message)); err != nil {
+		t.Fatal(err)
+	}
+	missing, err = tx.MissingSigners()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(missing) != 0 {
+		t.Fatalf("missing signers should be empty,got=%v", missing)
+	}
+	wireTx, err := tx.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, sig := range tx.Signatures {
+		if !ed25519.Verify(sig.PublicKey.Bytes(), message, sig.Signature) {
+			t.Fatalf("signature of [%s] verify error", sig.PublicKey.String())
+		}
+	}
+
+	// 与一次性签名的结果一致
+	tx2, _, _ := newTwoSignerTx(t)
+	tx2.Signatures = []*transaction.SignaturePubkeyPair{{PublicKey: tx.Signatures[0].PublicKey}}
+	if err := tx2.PartialSign([]*account.Account{account1, account2}); err != nil {
+		t.Fatal(err)
+	}
+	wireTx2, err := tx2.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(wireTx, wireTx2) {
+		t.Fatal("partial sign result is not equal to full sign")
+	}
+}
+
+func Test_PartialSignWireTransaction(t *testing.T) {
+	tx, account1, account2 := newTwoSignerTx(t)
+	if err := tx.PartialSign([]*account.Account{account2}); err != nil {
+		t.Fatal(err)
+	}
+	partialTx, err := tx.SerializeWithConfig(transaction.SerializeConfig{RequireAllSignatures: false})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 另一方解析部分签名的交易后补充签名
+	decoded, err := transaction.DeserializeTransaction(partialTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	missing, err := decoded.MissingSigners()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(missing) != 1 || !missing[0].Equals(account1.GetPublicKey()) {
+		t.Fatalf("missing signers error,got=%v", missing)
+	}
+	if err := decoded.PartialSign([]*account.Account{account1}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := decoded.Serialize(); err != nil {
+		t.Fatal(err)
+	}
+	other, err := account.NewAccount()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := decoded.PartialSign([]*account.Account{other}); err == nil {
+		t.Fatal("partial sign with non signer should fail")
+	}
+	if err := decoded.AddSignature(account1.GetPublicKey(), []byte{1, 2, 3}); err == nil {
+		t.Fatal("add signature with invalid length should fail")
+	}
+}
+
+func Test_PartialSignDeserialized(t *testing.T) {
+	account1, account2 := newTestAccounts()
+	signers := []*account.Account{account1, account2}
+	message := newForeignMessage(signers...)
+	expected := newForeignWireTx(message, signers, 2)
+
+	// 收到只有account1签名的交易，account2联署
+	decoded, err := transaction.DeserializeTransaction(newForeignWireTx(message, signers, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := decoded.PartialSign([]*account.Account{account2}); err != nil {
+		t.Fatal(err)
+	}
+	wireTx, err := decoded.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(wireTx, expected) {
+		t.Fatal("partial sign deserialized transaction error")
+	}
+
+	// 离线设备对SerializeMessage结果签名
+	decoded, err = transaction.DeserializeTransaction(newForeignWireTx(message, signers, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	signData, err := decoded.SerializeMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := decoded.AddSignature(account2.GetPublicKey(), account2.Sign(signData)); err != nil {
+		t.Fatal(err)
+	}
+	wireTx, err = decoded.SerializeWithConfig(transaction.SerializeConfig{
+		RequireAllSignatures: true,
+		VerifySignatures:     true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(wireTx, expected) {
+		t.Fatal("add signature to deserialized transaction error")
+	}
+}
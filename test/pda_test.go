@@ -0,0 +1,55 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/JFJun/solana-go/account"
+)
+
+/*
+测试用例来自web3.js的publickey.test.js
+*/
+func Test_CreateProgramAddress(t *testing.T) {
+	programId := account.MustPublicKeyFromBase58("BPFLoader1111111111111111111111111111111111")
+	publicKey := account.MustPublicKeyFromBase58("SeedPubey1111111111111111111111111111111111")
+	cases := []struct {
+		seeds  [][]byte
+		expect string
+	}{
+		{[][]byte{[]byte(""), {1}}, "3gF2KMe9KiC6FNVBmfg9i267aMPvK37FewCip4eGBFcT"},
+		{[][]byte{[]byte("☉")}, "7ytmC1nT1xY4RfxCV2ZgyA7UakC93do5ZdyhdF3EtPj7"},
+		{[][]byte{[]byte("Talking"), []byte("Squirrels")}, "HwRVBufQ4haG5XSgpspwKtNd3PC9GM9m1196uJW36vds"},
+		{[][]byte{publicKey[:]}, "GUs5qLUfsEHkcMB9T38vjr18ypEhRuNWiePW2LoK4E3K"},
+	}
+	for _, c := range cases {
+		address, err := account.CreateProgramAddress(c.seeds, programId)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if address.String() != c.expect {
+			t.Fatalf("program address error,expect=%s,got=%s", c.expect, address.String())
+		}
+	}
+	// seed超过32字节
+	if _, err := account.CreateProgramAddress([][]byte{make([]byte, 33)}, programId); err == nil {
+		t.Fatal("seed longer than 32 bytes should fail")
+	}
+}
+
+func Test_FindProgramAddress(t *testing.T) {
+	programId := account.MustPublicKeyFromBase58("BPFLoader1111111111111111111111111111111111")
+	address, bump, err := account.FindProgramAddress([][]byte{[]byte("")}, programId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect, err := account.CreateProgramAddress([][]byte{[]byte(""), {bump}}, programId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !address.Equals(expect) {
+		t.Fatalf("find program address error,expect=%s,got=%s", expect.String(), address.String())
+	}
+	if address.IsOnCurve() {
+		t.Fatal("program address should not be on curve")
+	}
+}
@@ -0,0 +1,127 @@
+package test
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/binary"
+	"encoding/hex"
+	"testing"
+
+	"github.com/JFJun/solana-go/ed25519program"
+	"github.com/JFJun/solana-go/secp256k1program"
+)
+
+func Test_Ed25519Instruction(t *testing.T) {
+	signer, _ := newTestAccounts()
+	message := []byte("attestation:42")
+	ins, err := ed25519program.NewInstructionWithAccount(ed25519program.InstructionWithAccountParams{
+		Account: signer,
+		Message: message,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ins.GetProgramId().Equals(ed25519program.ProgramId) || len(ins.GetKeys()) != 0 {
+		t.Fatal("ed25519 instruction error")
+	}
+	data := ins.GetData()
+	expectHeader := "0100" + "3000" + "ffff" + "1000" + "ffff" + "7000" + "0e00" + "ffff"
+	if hex.EncodeToString(data[:16]) != expectHeader {
+		t.Fatalf("ed25519 offsets error,got=%s", hex.EncodeToString(data[:16]))
+	}
+	pubkey, signature := data[16:48], data[48:112]
+	if !bytes.Equal(pubkey, signer.GetPublicKey().Bytes()) || !bytes.Equal(data[112:], message) {
+		t.Fatal("ed25519 instruction data error")
+	}
+	if !ed25519.Verify(pubkey, message, signature) {
+		t.Fatal("ed25519 signature is not valid")
+	}
+	if _, err := ed25519program.NewInstruction(ed25519program.InstructionParams{
+		PublicKey: signer.GetPublicKey(), Message: message, Signature: signature[:63],
+	}); err == nil {
+		t.Fatal("short signature should fail")
+	}
+}
+
+func Test_EthAddress(t *testing.T) {
+	if hex.EncodeToString(secp256k1program.Keccak256(nil)) != "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470" {
+		t.Fatal("keccak256 error")
+	}
+	// 以太坊文档中的示例私钥
+	privateKey, _ := hex.DecodeString("4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318")
+	message := []byte("hello solana")
+	signature, recoveryId, err := secp256k1program.Sign(privateKey, message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect, err := secp256k1program.ParseEthAddress("0x2c7536E3605D9C16a7a3D7b1898e529396a65c23")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address, err := secp256k1program.RecoverEthAddress(message, signature, recoveryId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(address, expect) {
+		t.Fatalf("recover eth address error,got=%s", hex.EncodeToString(address))
+	}
+	publicKey, err := secp256k1program.RecoverPublicKey(message, signature, recoveryId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromPublicKey, err := secp256k1program.PublicKeyToEthAddress(publicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(fromPublicKey, expect) {
+		t.Fatal("public key to eth address error")
+	}
+	// 消息被篡改后恢复出的地址不同
+	other, err := secp256k1program.RecoverEthAddress([]byte("hello solanA"), signature, recoveryId)
+	if err == nil && bytes.Equal(other, expect) {
+		t.Fatal("tampered message should not recover the same address")
+	}
+	if _, err := secp256k1program.ParseEthAddress("2c7536E3605D9C16a7a3D7b1898e529396a65c23"); err == nil {
+		t.Fatal("eth address without 0x should fail")
+	}
+}
+
+func Test_Secp256k1Instruction(t *testing.T) {
+	privateKey, _ := hex.DecodeString("4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318")
+	message := []byte("attestation:42")
+	ins, err := secp256k1program.NewInstructionWithPrivateKey(secp256k1program.InstructionWithPrivateKeyParams{
+		PrivateKey:       privateKey,
+		Message:          message,
+		InstructionIndex: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ins.GetProgramId().Equals(secp256k1program.ProgramId) || len(ins.GetKeys()) != 0 {
+		t.Fatal("secp256k1 instruction error")
+	}
+	data := ins.GetData()
+	expectHeader := "01" + "2000" + "01" + "0c00" + "01" + "6100" + "0e00" + "01"
+	if hex.EncodeToString(data[:12]) != expectHeader {
+		t.Fatalf("secp256k1 offsets error,got=%s", hex.EncodeToString(data[:12]))
+	}
+	signatureOffset := binary.LittleEndian.Uint16(data[1:3])
+	ethAddress := data[12:32]
+	signature := data[signatureOffset : signatureOffset+64]
+	recoveryId := data[signatureOffset+64]
+	if hex.EncodeToString(ethAddress) != "2c7536e3605d9c16a7a3d7b1898e529396a65c23" || !bytes.Equal(data[97:], message) {
+		t.Fatal("secp256k1 instruction data error")
+	}
+	recovered, err := secp256k1program.RecoverEthAddress(message, signature, recoveryId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(recovered, ethAddress) {
+		t.Fatal("secp256k1 instruction signature is not valid")
+	}
+	if _, err := secp256k1program.NewInstruction(secp256k1program.InstructionParams{
+		EthAddress: ethAddress, Message: message, Signature: signature, RecoveryId: 4,
+	}); err == nil {
+		t.Fatal("invalid recovery id should fail")
+	}
+}
@@ -0,0 +1,93 @@
+package test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/JFJun/solana-go/account"
+)
+
+func Test_PublicKeyFromBase58(t *testing.T) {
+	s := "9SvsEyncSPjZaqjEsGjfvgaQowxq1BTNTJo6imGxseyx"
+	pk, err := account.PublicKeyFromBase58(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pk.String() != s {
+		t.Fatalf("base58 round trip error,got=%s", pk.String())
+	}
+	system, err := account.PublicKeyFromBase58("11111111111111111111111111111111")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !system.IsZero() {
+		t.Fatal("system program id should be zero")
+	}
+	for _, bad := range []string{
+		"",
+		"9SvsEyncSPjZaqjEsGjfvgaQowxq1BTNTJo6imG",       // 长度不对
+		"9SvsEyncSPjZaqjEsGjfvgaQowxq1BTNTJo6imGxseyxx", // 长度不对
+		"0SvsEyncSPjZaqjEsGjfvgaQowxq1BTNTJo6imGxseyx",  // 非法字符0
+		"lSvsEyncSPjZaqjEsGjfvgaQowxq1BTNTJo6imGxseyx",  // 非法字符l
+	} {
+		if _, err := account.PublicKeyFromBase58(bad); err == nil {
+			t.Fatalf("public key [%s] should be invalid", bad)
+		}
+	}
+	if _, err := account.PublicKeyFromBytes(make([]byte, 31)); err == nil {
+		t.Fatal("31 bytes public key should be invalid")
+	}
+}
+
+func Test_PublicKeyEquals(t *testing.T) {
+	a := account.MustPublicKeyFromBase58("9SvsEyncSPjZaqjEsGjfvgaQowxq1BTNTJo6imGxseyx")
+	b := account.MustPublicKeyFromBase58("BHUNqtk5Vv6vfQTxpPjqWo2v8GPZJbqBonCaqhhK1Hub")
+	if a.Equals(b) {
+		t.Fatal("different public key should not be equal")
+	}
+	c, _ := account.PublicKeyFromBytes(a.Bytes())
+	if !a.Equals(c) {
+		t.Fatal("same public key should be equal")
+	}
+}
+
+func Test_PublicKeyIsOnCurve(t *testing.T) {
+	for i := 0; i < 10; i++ {
+		acc, err := account.NewAccount()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !acc.GetPublicKey().IsOnCurve() {
+			t.Fatalf("ed25519 public key [%s] should be on curve", acc.ToBase58())
+		}
+	}
+	// 来自web3.js的测试用例
+	offCurve := account.MustPublicKeyFromBase58("12rqwuEgBYiGhBrDJStCiqEtzQpTTiZbh7teNVLuYcFA")
+	if offCurve.IsOnCurve() {
+		t.Fatal("program address should not be on curve")
+	}
+}
+
+func Test_PublicKeyJSON(t *testing.T) {
+	type payload struct {
+		Key account.PublicKey `json:"key"`
+	}
+	p := payload{account.MustPublicKeyFromBase58("BHUNqtk5Vv6vfQTxpPjqWo2v8GPZJbqBonCaqhhK1Hub")}
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"key":"BHUNqtk5Vv6vfQTxpPjqWo2v8GPZJbqBonCaqhhK1Hub"}` {
+		t.Fatalf("marshal public key error,got=%s", string(data))
+	}
+	var q payload
+	if err := json.Unmarshal(data, &q); err != nil {
+		t.Fatal(err)
+	}
+	if !q.Key.Equals(p.Key) {
+		t.Fatal("unmarshal public key error")
+	}
+	if err := json.Unmarshal([]byte(`{"key":"abc"}`), &q); err == nil {
+		t.Fatal("unmarshal invalid public key should fail")
+	}
+}
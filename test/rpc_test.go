@@ -0,0 +1,68 @@
+package test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/JFJun/solana-go/account"
+	"github.com/JFJun/solana-go/rpc"
+)
+
+/*
+模拟rpc节点，按method返回固定的result
+*/
+func newMockRpcServer(t *testing.T, results map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		var req rpc.RequestBody
+		if err := json.Unmarshal(body, &req); err != nil {
+			t.Error(err)
+			return
+		}
+		result, ok := results[req.Method]
+		if !ok {
+			w.Write([]byte(`{"jsonrpc":"2.0","error":{"code":-32601,"message":"Method not found"},"id":1}`))
+			return
+		}
+		w.Write([]byte(`{"jsonrpc":"2.0","result":` + result + `,"id":1}`))
+	}))
+}
+
+func Test_RpcGetAccountInfo(t *testing.T) {
+	server := newMockRpcServer(t, map[string]string{
+		"getBalance": `{"context":{"slot":1},"value":1000000000}`,
+		"getAccountInfo": `{"context":{"slot":1},"value":{"data":["AQID","base64"],"executable":false,` +
+			`"lamports":1000000000,"owner":"11111111111111111111111111111111","rentEpoch":18446744073709551615}}`,
+	})
+	defer server.Close()
+	client := rpc.New(server.URL, "", "")
+	pubkey := account.MustPublicKeyFromBase58("9SvsEyncSPjZaqjEsGjfvgaQowxq1BTNTJo6imGxseyx")
+
+	balance, err := client.GetBalance(pubkey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance != 1000000000 {
+		t.Fatalf("balance error,got=%d", balance)
+	}
+	info, err := client.GetAccountInfo(pubkey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.Owner.IsZero() || info.Lamports != 1000000000 || info.RentEpoch != 18446744073709551615 {
+		t.Fatalf("account info error,got=%+v", info)
+	}
+	if len(info.Data) != 3 || info.Data[0] != 1 || info.Data[2] != 3 {
+		t.Fatalf("account data error,got=%v", info.Data)
+	}
+	if _, err := client.SendRequest("getFoo", nil); err == nil {
+		t.Fatal("unknown method should return error")
+	}
+}
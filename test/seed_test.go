@@ -0,0 +1,89 @@
+package test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/JFJun/solana-go/account"
+	"github.com/JFJun/solana-go/transaction"
+)
+
+func Test_CreateWithSeed(t *testing.T) {
+	defaultPublicKey := account.MustPublicKeyFromBase58("11111111111111111111111111111111")
+	derived, err := account.CreateWithSeed(defaultPublicKey, "limber chicken: 4/45", defaultPublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if derived.String() != "9h1HyLCW5dZnBVap8C5egQ9Z6pHyjsh5MNy83iPqqRuq" {
+		t.Fatalf("create with seed error,got=%s", derived.String())
+	}
+	if _, err := account.CreateWithSeed(defaultPublicKey, "012345678901234567890123456789012", defaultPublicKey); err == nil {
+		t.Fatal("seed longer than 32 bytes should fail")
+	}
+}
+
+func Test_CreateAccountWithSeed(t *testing.T) {
+	from := account.MustPublicKeyFromBase58("9SvsEyncSPjZaqjEsGjfvgaQowxq1BTNTJo6imGxseyx")
+	owner := account.MustPublicKeyFromBase58("BPFLoader1111111111111111111111111111111111")
+	newAccount, err := account.CreateWithSeed(from, "deposit-1", owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ins, err := transaction.NewCreateAccountWithSeed(transaction.CreateAccountWithSeedParams{
+		From:       from,
+		NewAccount: newAccount,
+		Base:       from,
+		Seed:       "deposit-1",
+		Lamports:   890880,
+		Space:      0,
+		ProgramId:  owner,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// base与from相同时不重复添加base
+	if len(ins.GetKeys()) != 2 {
+		t.Fatalf("keys length error,got=%d", len(ins.GetKeys()))
+	}
+	expect := new(bytes.Buffer)
+	binary.Write(expect, binary.LittleEndian, uint32(3))
+	expect.Write(from[:])
+	binary.Write(expect, binary.LittleEndian, uint64(9))
+	expect.WriteString("deposit-1")
+	binary.Write(expect, binary.LittleEndian, uint64(890880))
+	binary.Write(expect, binary.LittleEndian, uint64(0))
+	expect.Write(owner[:])
+	if !bytes.Equal(ins.GetData(), expect.Bytes()) {
+		t.Fatalf("create account with seed data error,got=%v", ins.GetData())
+	}
+}
+
+func Test_TransferWithSeed(t *testing.T) {
+	base := account.MustPublicKeyFromBase58("9SvsEyncSPjZaqjEsGjfvgaQowxq1BTNTJo6imGxseyx")
+	to := account.MustPublicKeyFromBase58("BHUNqtk5Vv6vfQTxpPjqWo2v8GPZJbqBonCaqhhK1Hub")
+	owner := account.MustPublicKeyFromBase58("11111111111111111111111111111111")
+	from, err := account.CreateWithSeed(base, "a", owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ins, err := transaction.NewTransferWithSeed(transaction.TransferWithSeedParams{
+		From:      from,
+		Base:      base,
+		To:        to,
+		Lamports:  123,
+		Seed:      "a",
+		ProgramId: owner,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := ins.GetKeys()
+	if len(keys) != 3 || keys[0].IsSigner || !keys[1].IsSigner || keys[1].IsWriteable || !keys[2].IsWriteable {
+		t.Fatal("transfer with seed keys error")
+	}
+	data := ins.GetData()
+	if len(data) != 4+8+8+1+32 || data[0] != 11 || data[4] != 123 || data[12] != 1 || data[20] != 'a' {
+		t.Fatalf("transfer with seed data error,got=%v", data)
+	}
+}
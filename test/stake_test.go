@@ -0,0 +1,195 @@
+package test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"math"
+	"testing"
+
+	"github.com/JFJun/solana-go/rpc"
+	"github.com/JFJun/solana-go/stake"
+	"github.com/JFJun/solana-go/systemprogram"
+	"github.com/JFJun/solana-go/sysvar"
+)
+
+func Test_CreateStakeAccount(t *testing.T) {
+	var (
+		from       = newTestPublicKey(1)
+		stakeKey   = newTestPublicKey(2)
+		staker     = newTestPublicKey(3)
+		withdrawer = newTestPublicKey(4)
+	)
+	ins, err := stake.NewCreateStakeAccount(stake.CreateStakeAccountParams{
+		From:        from,
+		StakePubkey: stakeKey,
+		Authorized:  stake.Authorized{Staker: staker, Withdrawer: withdrawer},
+		Lamports:    2282880,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ins) != 2 || !ins[0].GetProgramId().Equals(systemprogram.ProgramId) {
+		t.Fatal("create stake account error")
+	}
+	create := ins[0].GetData()
+	if binary.LittleEndian.Uint64(create[12:20]) != stake.StakeAccountLength || !bytes.Equal(create[20:52], stake.ProgramId.Bytes()) {
+		t.Fatal("create account data error")
+	}
+	checkInstruction(t, "initialize", ins[1], stake.ProgramId, []expectMeta{
+		{stakeKey, false, true}, {sysvar.RentPubkey, false, false},
+	}, "00000000"+hex.EncodeToString(staker[:])+hex.EncodeToString(withdrawer[:])+hex.EncodeToString(make([]byte, 48)))
+}
+
+func Test_StakeInstructions(t *testing.T) {
+	var (
+		stakeKey  = newTestPublicKey(1)
+		authority = newTestPublicKey(2)
+		vote      = newTestPublicKey(3)
+		other     = newTestPublicKey(4)
+		custodian = newTestPublicKey(5)
+	)
+	ins, err := stake.NewDelegateStake(stake.DelegateStakeParams{StakePubkey: stakeKey, AuthorizedPubkey: authority, VotePubkey: vote})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "delegate", ins, stake.ProgramId, []expectMeta{
+		{stakeKey, false, true}, {vote, false, false}, {sysvar.ClockPubkey, false, false},
+		{sysvar.StakeHistoryPubkey, false, false}, {stake.ConfigId, false, false}, {authority, true, false},
+	}, "02000000")
+
+	ins, err = stake.NewAuthorize(stake.AuthorizeParams{StakePubkey: stakeKey, AuthorizedPubkey: authority,
+		NewAuthorized: other, StakeAuthorize: stake.StakeAuthorizeWithdrawer, Custodian: &custodian})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "authorize", ins, stake.ProgramId, []expectMeta{
+		{stakeKey, false, true}, {sysvar.ClockPubkey, false, false}, {authority, true, false}, {custodian, true, false},
+	}, "01000000"+hex.EncodeToString(other[:])+"01000000")
+
+	ins, err = stake.NewAuthorizeChecked(stake.AuthorizeParams{StakePubkey: stakeKey, AuthorizedPubkey: authority,
+		NewAuthorized: other, StakeAuthorize: stake.StakeAuthorizeStaker})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "authorize checked", ins, stake.ProgramId, []expectMeta{
+		{stakeKey, false, true}, {sysvar.ClockPubkey, false, false}, {authority, true, false}, {other, true, false},
+	}, "0a00000000000000")
+
+	ins, err = stake.NewWithdraw(stake.WithdrawParams{StakePubkey: stakeKey, AuthorizedPubkey: authority, ToPubkey: other, Lamports: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "withdraw", ins, stake.ProgramId, []expectMeta{
+		{stakeKey, false, true}, {other, false, true}, {sysvar.ClockPubkey, false, false},
+		{sysvar.StakeHistoryPubkey, false, false}, {authority, true, false},
+	}, "04000000e803000000000000")
+
+	ins, err = stake.NewSplit(stake.SplitParams{StakePubkey: stakeKey, AuthorizedPubkey: authority, SplitStakePubkey: other, Lamports: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "split", ins, stake.ProgramId, []expectMeta{
+		{stakeKey, false, true}, {other, false, true}, {authority, true, false},
+	}, "03000000e803000000000000")
+
+	ins, err = stake.NewMerge(stake.MergeParams{StakePubkey: stakeKey, SourceStakePubkey: other, AuthorizedPubkey: authority})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "merge", ins, stake.ProgramId, []expectMeta{
+		{stakeKey, false, true}, {other, false, true}, {sysvar.ClockPubkey, false, false},
+		{sysvar.StakeHistoryPubkey, false, false}, {authority, true, false},
+	}, "07000000")
+
+	ins, err = stake.NewDeactivate(stake.DeactivateParams{StakePubkey: stakeKey, AuthorizedPubkey: authority})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "deactivate", ins, stake.ProgramId, []expectMeta{
+		{stakeKey, false, true}, {sysvar.ClockPubkey, false, false}, {authority, true, false},
+	}, "05000000")
+
+	epoch := uint64(300)
+	ins, err = stake.NewSetLockup(stake.SetLockupParams{StakePubkey: stakeKey, AuthorizedPubkey: authority, Epoch: &epoch})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "set lockup", ins, stake.ProgramId, []expectMeta{
+		{stakeKey, false, true}, {authority, true, false},
+	}, "0600000000012c0100000000000000")
+
+	ins, err = stake.NewInitializeChecked(stake.InitializeCheckedParams{StakePubkey: stakeKey,
+		Authorized: stake.Authorized{Staker: authority, Withdrawer: other}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "initialize checked", ins, stake.ProgramId, []expectMeta{
+		{stakeKey, false, true}, {sysvar.RentPubkey, false, false}, {authority, false, false}, {other, true, false},
+	}, "09000000")
+
+	ins, err = stake.NewRedelegate(stake.RedelegateParams{StakePubkey: stakeKey, AuthorizedPubkey: authority,
+		NewStakePubkey: other, NewVotePubkey: vote})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "redelegate", ins, stake.ProgramId, []expectMeta{
+		{stakeKey, false, true}, {other, false, true}, {vote, false, false}, {stake.ConfigId, false, false}, {authority, true, false},
+	}, "0f000000")
+}
+
+func Test_DeserializeStakeAccount(t *testing.T) {
+	var (
+		staker     = newTestPublicKey(3)
+		withdrawer = newTestPublicKey(4)
+		vote       = newTestPublicKey(5)
+	)
+	data := make([]byte, stake.StakeAccountLength)
+	binary.LittleEndian.PutUint32(data[0:4], uint32(stake.StakeStateStake))
+	binary.LittleEndian.PutUint64(data[4:12], 2282880)
+	copy(data[12:44], staker[:])
+	copy(data[44:76], withdrawer[:])
+	binary.LittleEndian.PutUint64(data[84:92], 10)
+	copy(data[124:156], vote[:])
+	binary.LittleEndian.PutUint64(data[156:164], 1000000000)
+	binary.LittleEndian.PutUint64(data[164:172], 400)
+	binary.LittleEndian.PutUint64(data[172:180], stake.ActiveDeactivationEpoch)
+	binary.LittleEndian.PutUint64(data[180:188], math.Float64bits(0.25))
+	binary.LittleEndian.PutUint64(data[188:196], 123456)
+
+	server := newMockRpcServer(t, map[string]string{
+		"getAccountInfo": `{"context":{"slot":1},"value":{"data":["` + base64.StdEncoding.EncodeToString(data) +
+			`","base64"],"executable":false,"lamports":1002282880,"owner":"Stake11111111111111111111111111111111111111","rentEpoch":0}}`,
+	})
+	defer server.Close()
+	sa, err := stake.GetStakeAccount(rpc.New(server.URL, "", ""), newTestPublicKey(9))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sa.IsDelegated() || sa.IsDeactivating() {
+		t.Fatal("stake state error")
+	}
+	if sa.Meta.RentExemptReserve != 2282880 || !sa.Meta.Authorized.Staker.Equals(staker) ||
+		!sa.Meta.Authorized.Withdrawer.Equals(withdrawer) || sa.Meta.Lockup.Epoch != 10 {
+		t.Fatalf("stake meta error,got=%+v", sa.Meta)
+	}
+	d := sa.Stake.Delegation
+	if !d.VoterPubkey.Equals(vote) || d.Stake != 1000000000 || d.ActivationEpoch != 400 ||
+		d.WarmupCooldownRate != 0.25 || sa.Stake.CreditsObserved != 123456 {
+		t.Fatalf("stake delegation error,got=%+v", sa.Stake)
+	}
+
+	binary.LittleEndian.PutUint32(data[0:4], uint32(stake.StakeStateInitialized))
+	sa, err = stake.DeserializeStakeAccount(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sa.Stake != nil || sa.Meta == nil {
+		t.Fatal("initialized stake account should not have delegation")
+	}
+	binary.LittleEndian.PutUint32(data[0:4], 4)
+	if _, err := stake.DeserializeStakeAccount(data); err == nil {
+		t.Fatal("invalid stake state should fail")
+	}
+}
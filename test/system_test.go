@@ -0,0 +1,164 @@
+package test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"testing"
+
+	"github.com/JFJun/solana-go/account"
+	"github.com/JFJun/solana-go/systemprogram"
+	"github.com/JFJun/solana-go/sysvar"
+	"github.com/JFJun/solana-go/transaction"
+)
+
+func Test_CreateAccount(t *testing.T) {
+	from := account.MustPublicKeyFromBase58("9SvsEyncSPjZaqjEsGjfvgaQowxq1BTNTJo6imGxseyx")
+	newAccount := account.MustPublicKeyFromBase58("BHUNqtk5Vv6vfQTxpPjqWo2v8GPZJbqBonCaqhhK1Hub")
+	ins, err := transaction.NewCreateAccount(transaction.CreateAccountParams{
+		From:       from,
+		NewAccount: newAccount,
+		Lamports:   1447680,
+		Space:      systemprogram.NonceAccountLength,
+		ProgramId:  systemprogram.ProgramId,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ins.GetProgramId().Equals(systemprogram.ProgramId) {
+		t.Fatal("program id error")
+	}
+	expect := new(bytes.Buffer)
+	binary.Write(expect, binary.LittleEndian, uint32(0))
+	binary.Write(expect, binary.LittleEndian, uint64(1447680))
+	binary.Write(expect, binary.LittleEndian, uint64(80))
+	expect.Write(make([]byte, 32))
+	if !bytes.Equal(ins.GetData(), expect.Bytes()) {
+		t.Fatalf("create account data error,got=%v", ins.GetData())
+	}
+	keys := ins.GetKeys()
+	if len(keys) != 2 || !keys[0].IsSigner || !keys[1].IsSigner || !keys[1].IsWriteable {
+		t.Fatal("create account keys error")
+	}
+}
+
+func Test_NonceInstructions(t *testing.T) {
+	nonce := account.MustPublicKeyFromBase58("9SvsEyncSPjZaqjEsGjfvgaQowxq1BTNTJo6imGxseyx")
+	authority := account.MustPublicKeyFromBase58("BHUNqtk5Vv6vfQTxpPjqWo2v8GPZJbqBonCaqhhK1Hub")
+
+	initialize, err := transaction.NewInitializeNonceAccount(transaction.InitializeNonceAccountParams{
+		Nonce:      nonce,
+		Authorized: authority,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := initialize.GetKeys()
+	if len(keys) != 3 || !keys[1].PubKey.Equals(sysvar.RecentBlockhashesPubkey) || !keys[2].PubKey.Equals(sysvar.RentPubkey) {
+		t.Fatal("initialize nonce account keys error")
+	}
+	if data := initialize.GetData(); len(data) != 36 || data[0] != 6 || !bytes.Equal(data[4:], authority[:]) {
+		t.Fatalf("initialize nonce account data error,got=%v", data)
+	}
+
+	advance, err := transaction.NewAdvanceNonceAccount(transaction.AdvanceNonceAccountParams{
+		Nonce:      nonce,
+		Authorized: authority,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(advance.GetData(), []byte{4, 0, 0, 0}) {
+		t.Fatalf("advance nonce account data error,got=%v", advance.GetData())
+	}
+	keys = advance.GetKeys()
+	if len(keys) != 3 || !keys[2].IsSigner || !keys[0].IsWriteable {
+		t.Fatal("advance nonce account keys error")
+	}
+
+	withdraw, err := transaction.NewWithdrawNonceAccount(transaction.WithdrawNonceAccountParams{
+		Nonce:      nonce,
+		Authorized: authority,
+		To:         authority,
+		Lamports:   5000,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(withdraw.GetKeys()) != 5 || !bytes.Equal(withdraw.GetData(), []byte{5, 0, 0, 0, 0x88, 0x13, 0, 0, 0, 0, 0, 0}) {
+		t.Fatalf("withdraw nonce account error,got=%v", withdraw.GetData())
+	}
+
+	upgrade, err := transaction.NewUpgradeNonceAccount(transaction.UpgradeNonceAccountParams{Nonce: nonce})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(upgrade.GetData(), []byte{12, 0, 0, 0}) {
+		t.Fatalf("upgrade nonce account data error,got=%v", upgrade.GetData())
+	}
+}
+
+func Test_SystemAccountInstructions(t *testing.T) {
+	var (
+		acc       = newTestPublicKey(1)
+		base      = newTestPublicKey(2)
+		owner     = newTestPublicKey(3)
+		authority = newTestPublicKey(4)
+		newAuth   = newTestPublicKey(5)
+		seed      = "vault"
+		seedHex   = "0500000000000000" + hex.EncodeToString([]byte(seed))
+	)
+	ins, err := transaction.NewAssign(transaction.AssignParams{Account: acc, ProgramId: owner})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "assign", ins, systemprogram.ProgramId, []expectMeta{
+		{acc, true, true},
+	}, "01000000"+hex.EncodeToString(owner[:]))
+
+	ins, err = transaction.NewAllocate(transaction.AllocateParams{Account: acc, Space: 165})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "allocate", ins, systemprogram.ProgramId, []expectMeta{
+		{acc, true, true},
+	}, "08000000"+"a500000000000000")
+
+	ins, err = transaction.NewAuthorizeNonceAccount(transaction.AuthorizeNonceAccountParams{
+		Nonce: acc, Authorized: authority, NewAuthorized: newAuth,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "authorize nonce account", ins, systemprogram.ProgramId, []expectMeta{
+		{acc, false, true}, {authority, true, false},
+	}, "07000000"+hex.EncodeToString(newAuth[:]))
+
+	ins, err = transaction.NewAllocateWithSeed(transaction.AllocateWithSeedParams{
+		Account: acc, Base: base, Seed: seed, Space: 200, ProgramId: owner,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "allocate with seed", ins, systemprogram.ProgramId, []expectMeta{
+		{acc, false, true}, {base, true, false},
+	}, "09000000"+hex.EncodeToString(base[:])+seedHex+"c800000000000000"+hex.EncodeToString(owner[:]))
+
+	ins, err = transaction.NewAssignWithSeed(transaction.AssignWithSeedParams{
+		Account: acc, Base: base, Seed: seed, ProgramId: owner,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "assign with seed", ins, systemprogram.ProgramId, []expectMeta{
+		{acc, false, true}, {base, true, false},
+	}, "0a000000"+hex.EncodeToString(base[:])+seedHex+hex.EncodeToString(owner[:]))
+
+	longSeed := string(bytes.Repeat([]byte{'a'}, account.MaxSeedLength+1))
+	if _, err := transaction.NewAllocateWithSeed(transaction.AllocateWithSeedParams{Account: acc, Base: base, Seed: longSeed}); err == nil {
+		t.Fatal("allocate with too long seed should fail")
+	}
+	if _, err := transaction.NewAssignWithSeed(transaction.AssignWithSeedParams{Account: acc, Base: base, Seed: longSeed}); err == nil {
+		t.Fatal("assign with too long seed should fail")
+	}
+}
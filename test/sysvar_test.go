@@ -0,0 +1,217 @@
+package test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/JFJun/solana-go/account"
+	"github.com/JFJun/solana-go/rpc"
+	"github.com/JFJun/solana-go/sysvar"
+	"github.com/btcsuite/btcutil/base58"
+)
+
+func putU64s(values ...uint64) []byte {
+	data := make([]byte, 8*len(values))
+	for i, v := range values {
+		binary.LittleEndian.PutUint64(data[i*8:], v)
+	}
+	return data
+}
+
+func Test_SysvarClockAndRent(t *testing.T) {
+	clockData := putU64s(250000000, uint64(1700000000), 580, 581, uint64(1700086400))
+	clock, err := sysvar.DeserializeClock(clockData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectClock := sysvar.Clock{Slot: 250000000, EpochStartTimestamp: 1700000000, Epoch: 580, LeaderScheduleEpoch: 581, UnixTimestamp: 1700086400}
+	if *clock != expectClock {
+		t.Fatalf("clock error,got=%+v", *clock)
+	}
+	if _, err := sysvar.DeserializeClock(clockData[:39]); err == nil {
+		t.Fatal("short clock data should fail")
+	}
+
+	// 主网的rent参数
+	rentData := append(putU64s(3480, math.Float64bits(2.0)), 50)
+	rent, err := sysvar.DeserializeRent(rentData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rent.LamportsPerByteYear != 3480 || rent.ExemptionThreshold != 2.0 || rent.BurnPercent != 50 {
+		t.Fatalf("rent error,got=%+v", *rent)
+	}
+	if rent.MinimumBalance(0) != 890880 || rent.MinimumBalance(165) != 2039280 {
+		t.Fatal("rent minimum balance error")
+	}
+
+	server := newMockRpcServer(t, map[string]string{
+		"getAccountInfo": `{"context":{"slot":1},"value":{"data":["` + base64.StdEncoding.EncodeToString(clockData) + `","base64"],` +
+			`"executable":false,"lamports":1169280,"owner":"Sysvar1111111111111111111111111111111111111","rentEpoch":0}}`,
+	})
+	defer server.Close()
+	fetched, err := sysvar.GetClock(rpc.New(server.URL, "", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *fetched != expectClock {
+		t.Fatal("get clock error")
+	}
+	server2 := newMockRpcServer(t, map[string]string{
+		"getAccountInfo": `{"context":{"slot":1},"value":{"data":["` + base64.StdEncoding.EncodeToString(rentData) + `","base64"],` +
+			`"executable":false,"lamports":1009200,"owner":"11111111111111111111111111111111","rentEpoch":0}}`,
+	})
+	defer server2.Close()
+	if _, err := sysvar.GetRent(rpc.New(server2.URL, "", "")); err == nil {
+		t.Fatal("account not owned by sysvar should fail")
+	}
+}
+
+func Test_SysvarEpochSchedule(t *testing.T) {
+	// 主网：432000 slots per epoch，带预热
+	data := putU64s(432000, 432000)
+	data = append(data, 1)
+	data = append(data, putU64s(14, 524256)...)
+	schedule, err := sysvar.DeserializeEpochSchedule(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !schedule.Warmup || schedule.FirstNormalEpoch != 14 || schedule.FirstNormalSlot != 524256 {
+		t.Fatalf("epoch schedule error,got=%+v", *schedule)
+	}
+	if schedule.GetSlotsInEpoch(0) != 32 || schedule.GetSlotsInEpoch(13) != 262144 || schedule.GetSlotsInEpoch(14) != 432000 {
+		t.Fatal("slots in epoch error")
+	}
+	cases := []struct{ slot, epoch, index uint64 }{
+		{0, 0, 0}, {31, 0, 31}, {32, 1, 0}, {95, 1, 63}, {96, 2, 0},
+		{524255, 13, 262143}, {524256, 14, 0}, {524256 + 432000*3 + 7, 17, 7},
+	}
+	for _, c := range cases {
+		epoch, index := schedule.GetEpochAndSlotIndex(c.slot)
+		if epoch != c.epoch || index != c.index {
+			t.Fatalf("slot %d epoch error,got=(%d,%d)", c.slot, epoch, index)
+		}
+	}
+	data[16] = 2
+	if _, err := sysvar.DeserializeEpochSchedule(data); err == nil {
+		t.Fatal("invalid bool should fail")
+	}
+}
+
+func Test_SysvarVectors(t *testing.T) {
+	hash1, hash2 := newTestPublicKey(1), newTestPublicKey(2)
+
+	// slot hashes账户数据有填充
+	slotHashes := putU64s(2, 100)
+	slotHashes = append(slotHashes, hash1[:]...)
+	slotHashes = append(slotHashes, putU64s(99)...)
+	slotHashes = append(slotHashes, hash2[:]...)
+	slotHashes = append(slotHashes, make([]byte, 40)...)
+	hashes, err := sysvar.DeserializeSlotHashes(slotHashes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hashes) != 2 || hashes[0].Slot != 100 || hashes[0].Hash != hash1.String() || hashes[1].Slot != 99 || hashes[1].Hash != hash2.String() {
+		t.Fatalf("slot hashes error,got=%+v", hashes)
+	}
+	binary.LittleEndian.PutUint64(slotHashes, 4)
+	if _, err := sysvar.DeserializeSlotHashes(slotHashes); err == nil {
+		t.Fatal("slot hashes count out of range should fail")
+	}
+
+	recent := putU64s(1)
+	recent = append(recent, hash1[:]...)
+	recent = append(recent, putU64s(5000)...)
+	blockhashes, err := sysvar.DeserializeRecentBlockhashes(recent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blockhashes) != 1 || blockhashes[0].Blockhash != base58.Encode(hash1[:]) || blockhashes[0].LamportsPerSignature != 5000 {
+		t.Fatal("recent blockhashes error")
+	}
+
+	history, err := sysvar.DeserializeStakeHistory(putU64s(1, 580, 1000, 20, 30))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 1 || history[0] != (sysvar.StakeHistoryEntry{Epoch: 580, Effective: 1000, Activating: 20, Deactivating: 30}) {
+		t.Fatal("stake history error")
+	}
+
+	fees, err := sysvar.DeserializeFees(putU64s(5000))
+	if err != nil || fees.LamportsPerSignature != 5000 {
+		t.Fatal("fees error")
+	}
+	restart, err := sysvar.DeserializeLastRestartSlot(putU64s(123))
+	if err != nil || restart.LastRestartSlot != 123 {
+		t.Fatal("last restart slot error")
+	}
+}
+
+func Test_SysvarEpochRewards(t *testing.T) {
+	parent := newTestPublicKey(9)
+	data := putU64s(1000, 4)
+	data = append(data, parent[:]...)
+	data = append(data, putU64s(math.MaxUint64, 1)...) // u128 total points = 2^65-1
+	data = append(data, putU64s(500, 200)...)
+	data = append(data, 1)
+	rewards, err := sysvar.DeserializeEpochRewards(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rewards.DistributionStartingBlockHeight != 1000 || rewards.NumPartitions != 4 || rewards.ParentBlockhash != parent.String() ||
+		rewards.TotalRewards != 500 || rewards.DistributedRewards != 200 || !rewards.Active {
+		t.Fatalf("epoch rewards error,got=%+v", *rewards)
+	}
+	if rewards.TotalPoints.String() != "36893488147419103231" {
+		t.Fatalf("epoch rewards total points error,got=%s", rewards.TotalPoints.String())
+	}
+}
+
+func Test_SysvarInstructions(t *testing.T) {
+	var (
+		program = newTestPublicKey(7)
+		signer  = newTestPublicKey(1)
+		other   = newTestPublicKey(2)
+	)
+	encode := func(accounts []account.PublicKey, flags []byte, data []byte) []byte {
+		buf := make([]byte, 2)
+		binary.LittleEndian.PutUint16(buf, uint16(len(accounts)))
+		for i, a := range accounts {
+			buf = append(buf, flags[i])
+			buf = append(buf, a[:]...)
+		}
+		buf = append(buf, program[:]...)
+		buf = append(buf, byte(len(data)), byte(len(data)>>8))
+		return append(buf, data...)
+	}
+	ins0 := encode([]account.PublicKey{signer, other}, []byte{3, 2}, []byte{1, 2, 3})
+	ins1 := encode(nil, nil, []byte{})
+	data := []byte{2, 0}
+	data = append(data, byte(6), 0, byte(6+len(ins0)), 0)
+	data = append(data, ins0...)
+	data = append(data, ins1...)
+	data = append(data, 1, 0) // 当前指令序号
+	instructions, current, err := sysvar.DeserializeInstructions(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current != 1 || len(instructions) != 2 {
+		t.Fatal("instructions sysvar error")
+	}
+	accounts := instructions[0].Accounts
+	if len(accounts) != 2 || !accounts[0].PubKey.Equals(signer) || !accounts[0].IsSigner || !accounts[0].IsWriteable ||
+		accounts[1].IsSigner || !accounts[1].IsWriteable || !instructions[0].ProgramId.Equals(program) ||
+		!bytes.Equal(instructions[0].Data, []byte{1, 2, 3}) {
+		t.Fatalf("instruction 0 error,got=%+v", instructions[0])
+	}
+	if len(instructions[1].Accounts) != 0 || len(instructions[1].Data) != 0 {
+		t.Fatal("instruction 1 error")
+	}
+	if _, _, err := sysvar.DeserializeInstructions(data[:len(data)-10]); err == nil {
+		t.Fatal("truncated instructions sysvar should fail")
+	}
+}
@@ -0,0 +1,71 @@
+/*
+生成compile_message_test.go中的golden vector
+运行：npm install @solana/web3.js@1 && node test/testdata/web3_vectors.js
+所有账户都由Keypair.fromSeed(32个相同字节)生成，签名是确定性的
+*/
+const {
+  ComputeBudgetProgram,
+  Keypair,
+  PublicKey,
+  SystemProgram,
+  Transaction,
+  TransactionInstruction,
+} = require('@solana/web3.js');
+
+const blockhash = 'EETubP5AKHgjPAhzPAFcb8BAY1hMH639CWCFTqi3hq1k';
+const tokenProgramId = new PublicKey('TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA');
+const memoProgramId = new PublicKey('MemoSq4gqABAXKb96qnH8TysNcWxMyWCqXgDLGmfcHr');
+const seed = b => Keypair.fromSeed(new Uint8Array(32).fill(b));
+
+// token TransferChecked + memo + compute budget + system transfer，每一类账户都有多个
+function tokenTransferWithMemo() {
+  const [payer, owner, cosigner, funder] = [seed(11), seed(12), seed(13), seed(14)];
+  const [source, destination, mint, recipient] = [seed(21), seed(22), seed(23), seed(24)].map(k => k.publicKey);
+  const transferData = Buffer.alloc(10);
+  transferData[0] = 12;
+  transferData.writeBigUInt64LE(1500000n, 1);
+  transferData[9] = 6;
+  const tx = new Transaction().add(
+    ComputeBudgetProgram.setComputeUnitLimit({ units: 60000 }),
+    ComputeBudgetProgram.setComputeUnitPrice({ microLamports: 25000 }),
+    new TransactionInstruction({
+      programId: tokenProgramId,
+      keys: [
+        { pubkey: source, isSigner: false, isWritable: true },
+        { pubkey: mint, isSigner: false, isWritable: false },
+        { pubkey: destination, isSigner: false, isWritable: true },
+        { pubkey: owner.publicKey, isSigner: true, isWritable: false },
+      ],
+      data: transferData,
+    }),
+    new TransactionInstruction({
+      programId: memoProgramId,
+      keys: [
+        { pubkey: owner.publicKey, isSigner: true, isWritable: false },
+        { pubkey: cosigner.publicKey, isSigner: true, isWritable: false },
+      ],
+      data: Buffer.from('invoice-2048', 'utf8'),
+    }),
+    SystemProgram.transfer({ fromPubkey: funder.publicKey, toPubkey: recipient, lamports: 5000 }),
+  );
+  tx.recentBlockhash = blockhash;
+  tx.feePayer = payer.publicKey;
+  tx.sign(payer, owner, cosigner, funder);
+  return tx.serialize().toString('base64');
+}
+
+// 一个付款账户向多个地址转账，手续费支付者按base58排序并不在最前
+function batchTransfer() {
+  const payer = seed(31);
+  const tx = new Transaction();
+  for (let i = 0; i < 5; i++) {
+    tx.add(SystemProgram.transfer({ fromPubkey: payer.publicKey, toPubkey: seed(40 + i).publicKey, lamports: 1000 * (i + 1) }));
+  }
+  tx.recentBlockhash = blockhash;
+  tx.feePayer = payer.publicKey;
+  tx.sign(payer);
+  return tx.serialize().toString('base64');
+}
+
+console.log('tokenTransferWithMemo:', tokenTransferWithMemo());
+console.log('batchTransfer:', batchTransfer());
@@ -0,0 +1,284 @@
+package test
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"testing"
+
+	"github.com/JFJun/solana-go/account"
+	"github.com/JFJun/solana-go/token"
+)
+
+func appendTLV(data []byte, extType token.ExtensionType, value []byte) []byte {
+	header := make([]byte, 4)
+	binary.LittleEndian.PutUint16(header[0:2], uint16(extType))
+	binary.LittleEndian.PutUint16(header[2:4], uint16(len(value)))
+	return append(append(data, header...), value...)
+}
+
+func appendBorshString(data []byte, s string) []byte {
+	length := make([]byte, 4)
+	binary.LittleEndian.PutUint32(length, uint32(len(s)))
+	return append(append(data, length...), s...)
+}
+
+func newTransferFeeConfigData(authority account.PublicKey) []byte {
+	data := make([]byte, 108)
+	copy(data[0:32], authority[:])
+	binary.LittleEndian.PutUint64(data[64:72], 77)
+	// older: epoch 0, max 1000, 50bps; newer: epoch 300, max 5000, 100bps
+	binary.LittleEndian.PutUint64(data[80:88], 1000)
+	binary.LittleEndian.PutUint16(data[88:90], 50)
+	binary.LittleEndian.PutUint64(data[90:98], 300)
+	binary.LittleEndian.PutUint64(data[98:106], 5000)
+	binary.LittleEndian.PutUint16(data[106:108], 100)
+	return data
+}
+
+func Test_DeserializeMintWithExtensions(t *testing.T) {
+	authority := newTestPublicKey(1)
+	mintKey := newTestPublicKey(2)
+	data := newMintData(&authority, nil, 100, 6)
+	data = append(data, make([]byte, token.AccountSize-token.MintSize)...)
+	data = append(data, token.AccountTypeMint)
+	data = appendTLV(data, token.ExtensionTransferFeeConfig, newTransferFeeConfigData(authority))
+	data = appendTLV(data, token.ExtensionMetadataPointer, append(authority.Bytes(), mintKey.Bytes()...))
+	metadata := append(authority.Bytes(), mintKey.Bytes()...)
+	metadata = appendBorshString(metadata, "USD Coin")
+	metadata = appendBorshString(metadata, "USDC")
+	metadata = appendBorshString(metadata, "https://example.com/usdc.json")
+	metadata = append(metadata, 1, 0, 0, 0)
+	metadata = appendBorshString(metadata, "issuer")
+	metadata = appendBorshString(metadata, "circle")
+	data = appendTLV(data, token.ExtensionTokenMetadata, metadata)
+
+	mint, err := token.DeserializeMint(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mint.Supply != 100 || mint.Decimals != 6 || len(mint.Extensions) != 3 {
+		t.Fatalf("mint error,got=%+v", mint)
+	}
+	config, err := mint.Extensions.TransferFeeConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.WithdrawWithheldAuthority != nil || !config.TransferFeeConfigAuthority.Equals(authority) || config.WithheldAmount != 77 {
+		t.Fatalf("transfer fee config error,got=%+v", config)
+	}
+	// 50bps：ceil(1001*50/10000)=6
+	if fee := config.CalculateEpochFee(299, 1001); fee != 6 {
+		t.Fatalf("older transfer fee error,got=%d", fee)
+	}
+	// 100bps但不超过5000
+	if fee := config.CalculateEpochFee(300, 1000000); fee != 5000 {
+		t.Fatalf("newer transfer fee error,got=%d", fee)
+	}
+	if fee := config.CalculateEpochFee(300, ^uint64(0)); fee != 5000 {
+		t.Fatalf("overflow transfer fee error,got=%d", fee)
+	}
+	pointer, err := mint.Extensions.MetadataPointer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pointer.MetadataAddress.Equals(mintKey) {
+		t.Fatal("metadata pointer error")
+	}
+	md, err := mint.Extensions.TokenMetadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if md.Name != "USD Coin" || md.Symbol != "USDC" || md.Uri != "https://example.com/usdc.json" ||
+		len(md.AdditionalMetadata) != 1 || md.AdditionalMetadata[0] != [2]string{"issuer", "circle"} {
+		t.Fatalf("token metadata error,got=%+v", md)
+	}
+	if hook, err := mint.Extensions.TransferHook(); hook != nil || err != nil {
+		t.Fatal("missing extension should be nil")
+	}
+	// account type错误
+	data[token.AccountSize] = token.AccountTypeAccount
+	if _, err := token.DeserializeMint(data); err == nil {
+		t.Fatal("account type error should fail")
+	}
+}
+
+func Test_DeserializeAccountWithExtensions(t *testing.T) {
+	data := make([]byte, token.AccountSize)
+	data[108] = byte(token.AccountStateInitialized)
+	data = append(data, token.AccountTypeAccount)
+	data = appendTLV(data, token.ExtensionImmutableOwner, nil)
+	data = appendTLV(data, token.ExtensionMemoTransfer, []byte{1})
+	data = appendTLV(data, token.ExtensionTransferFeeAmount, []byte{9, 0, 0, 0, 0, 0, 0, 0})
+	// 未使用的空间
+	data = append(data, make([]byte, 8)...)
+	acc, err := token.DeserializeAccount(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !acc.Extensions.IsImmutableOwner() || !acc.Extensions.IsMemoRequired() || acc.Extensions.IsNonTransferable() {
+		t.Fatal("account extensions error")
+	}
+	amount, err := acc.Extensions.TransferFeeAmount()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if amount.WithheldAmount != 9 {
+		t.Fatal("transfer fee amount error")
+	}
+	data = appendTLV(data[:len(data)-8], token.ExtensionCpiGuard, []byte{1, 2})
+	if _, err := token.DeserializeAccount(data[:len(data)-1]); err == nil {
+		t.Fatal("truncated extension should fail")
+	}
+}
+
+func Test_Token2022Len(t *testing.T) {
+	mintLen, err := token.GetMintLen([]token.ExtensionType{token.ExtensionTransferFeeConfig}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mintLen != 278 {
+		t.Fatalf("mint len error,got=%d", mintLen)
+	}
+	accountLen, err := token.GetAccountLen([]token.ExtensionType{token.ExtensionImmutableOwner})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if accountLen != 170 {
+		t.Fatalf("account len error,got=%d", accountLen)
+	}
+	// 165 + 1 + 4 + 185 = 355，与多签账户长度相同时多加2字节
+	multisigLen, err := token.GetMintLen(nil, 185)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if multisigLen != 357 {
+		t.Fatalf("mint len equal to multisig size error,got=%d", multisigLen)
+	}
+	// web3.js: getMintLen([ExtensionType.MetadataPointer], {[ExtensionType.TokenMetadata]: 93})
+	// name="name" symbol="symbol" uri="uri"的TokenMetadata内容为93字节：165 + 1 + (4+64) + (4+93)
+	metadataLen, err := token.GetMintLen([]token.ExtensionType{token.ExtensionMetadataPointer}, 93)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if metadataLen != 331 {
+		t.Fatalf("mint len with token metadata error,got=%d", metadataLen)
+	}
+	if _, err := token.GetMintLen([]token.ExtensionType{token.ExtensionTokenMetadata}, 0); err == nil {
+		t.Fatal("variable length extension should fail")
+	}
+}
+
+func Test_Token2022Instructions(t *testing.T) {
+	var (
+		source    = newTestPublicKey(1)
+		mint      = newTestPublicKey(2)
+		dest      = newTestPublicKey(3)
+		owner     = newTestPublicKey(4)
+		authority = newTestPublicKey(5)
+	)
+	ins, err := token.NewTransferCheckedWithFee(token.TransferCheckedWithFeeParams{
+		Source: source, Mint: mint, Destination: dest, Owner: owner, Amount: 1000000, Decimals: 6, Fee: 5000,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "transfer checked with fee", ins, token.Token2022ProgramId, []expectMeta{
+		{source, false, true}, {mint, false, false}, {dest, false, true}, {owner, true, false},
+	}, "1a0140420f0000000000068813000000000000")
+
+	ins, err = token.NewHarvestWithheldTokensToMint(token.HarvestWithheldTokensToMintParams{
+		Mint: mint, Sources: []account.PublicKey{source, dest},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "harvest", ins, token.Token2022ProgramId, []expectMeta{
+		{mint, false, true}, {source, false, true}, {dest, false, true},
+	}, "1a04")
+
+	ins, err = token.NewInitializeTransferFeeConfig(token.InitializeTransferFeeConfigParams{
+		Mint: mint, TransferFeeConfigAuthority: &authority, TransferFeeBasisPoints: 100, MaximumFee: 5000,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "initialize transfer fee config", ins, token.Token2022ProgramId, []expectMeta{
+		{mint, false, true},
+	}, "1a0001"+hex.EncodeToString(authority[:])+"0064008813000000000000")
+
+	ins, err = token.NewInitializeInterestBearingMint(token.InitializeInterestBearingMintParams{
+		Mint: mint, RateAuthority: authority, Rate: -2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "initialize interest bearing", ins, token.Token2022ProgramId, []expectMeta{
+		{mint, false, true},
+	}, "2100"+hex.EncodeToString(authority[:])+"feff")
+
+	ins, err = token.NewInitializeDefaultAccountState(token.InitializeDefaultAccountStateParams{
+		Mint: mint, State: token.AccountStateFrozen,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "initialize default account state", ins, token.Token2022ProgramId, []expectMeta{
+		{mint, false, true},
+	}, "1c0002")
+
+	ins, err = token.NewEnableRequiredMemoTransfers(token.MemoTransferParams{Account: source, Owner: owner})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "enable memo transfer", ins, token.Token2022ProgramId, []expectMeta{
+		{source, false, true}, {owner, true, false},
+	}, "1e00")
+
+	ins, err = token.NewInitializeTokenMetadata(token.InitializeTokenMetadataParams{
+		Metadata: mint, UpdateAuthority: authority, Mint: mint, MintAuthority: owner,
+		Name: "A", Symbol: "B", Uri: "C",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// discriminator来自spl-token-metadata-interface
+	checkInstruction(t, "initialize token metadata", ins, token.Token2022ProgramId, []expectMeta{
+		{mint, false, true}, {authority, false, false}, {mint, false, false}, {owner, true, false},
+	}, "d2e11ea258b84d8d"+"0100000041"+"0100000042"+"0100000043")
+}
+
+func Test_Token2022SetAuthority(t *testing.T) {
+	var (
+		mint      = newTestPublicKey(1)
+		authority = newTestPublicKey(2)
+		other     = newTestPublicKey(3)
+	)
+	ins, err := token.NewSetAuthority(token.SetAuthorityParams{Account: mint, CurrentAuthority: authority,
+		AuthorityType: token.AuthorityCloseMint, NewAuthority: &other, ProgramId: token.Token2022ProgramId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "set close mint authority", ins, token.Token2022ProgramId, []expectMeta{
+		{mint, false, true}, {authority, true, false},
+	}, "060601"+hex.EncodeToString(other[:]))
+	// 撤销authority
+	ins, err = token.NewSetAuthority(token.SetAuthorityParams{Account: mint, CurrentAuthority: authority,
+		AuthorityType: token.AuthorityGroupMemberPointer, ProgramId: token.Token2022ProgramId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "revoke group member pointer authority", ins, token.Token2022ProgramId, []expectMeta{
+		{mint, false, true}, {authority, true, false},
+	}, "060e00"+hex.EncodeToString(make([]byte, 32)))
+	if token.AuthorityTransferFeeConfig != 4 || token.AuthorityMetadataPointer != 12 {
+		t.Fatal("token-2022 authority type value error")
+	}
+	if _, err := token.NewSetAuthority(token.SetAuthorityParams{Account: mint, CurrentAuthority: authority,
+		AuthorityType: token.AuthorityCloseMint}); err == nil {
+		t.Fatal("token-2022 authority type on token program should fail")
+	}
+	if _, err := token.NewSetAuthority(token.SetAuthorityParams{Account: mint, CurrentAuthority: authority,
+		AuthorityType: token.AuthorityGroupMemberPointer + 1, ProgramId: token.Token2022ProgramId}); err == nil {
+		t.Fatal("unknown authority type should fail")
+	}
+}
@@ -0,0 +1,121 @@
+package test
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"testing"
+
+	"github.com/JFJun/solana-go/account"
+	"github.com/JFJun/solana-go/rpc"
+	"github.com/JFJun/solana-go/token"
+)
+
+func putPublicKeyOption(data []byte, pubkey *account.PublicKey) {
+	if pubkey == nil {
+		return
+	}
+	binary.LittleEndian.PutUint32(data[0:4], 1)
+	copy(data[4:36], pubkey[:])
+}
+
+func newMintData(mintAuthority, freezeAuthority *account.PublicKey, supply uint64, decimals uint8) []byte {
+	data := make([]byte, token.MintSize)
+	putPublicKeyOption(data[0:36], mintAuthority)
+	binary.LittleEndian.PutUint64(data[36:44], supply)
+	data[44] = decimals
+	data[45] = 1
+	putPublicKeyOption(data[46:82], freezeAuthority)
+	return data
+}
+
+func Test_DeserializeMint(t *testing.T) {
+	authority := newTestPublicKey(1)
+	mint, err := token.DeserializeMint(newMintData(&authority, nil, 5000000000, 6))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mint.MintAuthority == nil || !mint.MintAuthority.Equals(authority) || mint.FreezeAuthority != nil {
+		t.Fatal("mint authority error")
+	}
+	if mint.Supply != 5000000000 || mint.Decimals != 6 || !mint.IsInitialized {
+		t.Fatalf("mint error,got=%+v", mint)
+	}
+	bad := newMintData(&authority, nil, 0, 6)
+	bad[0] = 2
+	if _, err := token.DeserializeMint(bad); err == nil {
+		t.Fatal("invalid coption tag should fail")
+	}
+	if _, err := token.DeserializeMint(make([]byte, token.AccountSize)); err == nil {
+		t.Fatal("token account data should not be decoded as mint")
+	}
+}
+
+func Test_DeserializeTokenAccount(t *testing.T) {
+	var (
+		owner    = newTestPublicKey(2)
+		delegate = newTestPublicKey(3)
+		closer   = newTestPublicKey(4)
+	)
+	data := make([]byte, token.AccountSize)
+	copy(data[0:32], token.NativeMint[:])
+	copy(data[32:64], owner[:])
+	binary.LittleEndian.PutUint64(data[64:72], 1000)
+	putPublicKeyOption(data[72:108], &delegate)
+	data[108] = byte(token.AccountStateFrozen)
+	binary.LittleEndian.PutUint32(data[109:113], 1)
+	binary.LittleEndian.PutUint64(data[113:121], 2039280)
+	binary.LittleEndian.PutUint64(data[121:129], 400)
+	putPublicKeyOption(data[129:165], &closer)
+
+	server := newMockRpcServer(t, map[string]string{
+		"getAccountInfo": `{"context":{"slot":1},"value":{"data":["` + base64.StdEncoding.EncodeToString(data) +
+			`","base64"],"executable":false,"lamports":2040280,"owner":"` + token.ProgramId.String() + `","rentEpoch":0}}`,
+	})
+	defer server.Close()
+	client := rpc.New(server.URL, "", "")
+	acc, err := token.GetAccount(client, newTestPublicKey(9), account.PublicKey{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !acc.Mint.Equals(token.NativeMint) || !acc.Owner.Equals(owner) || acc.Amount != 1000 || !acc.IsFrozen() {
+		t.Fatalf("token account error,got=%+v", acc)
+	}
+	if acc.Delegate == nil || !acc.Delegate.Equals(delegate) || acc.DelegatedAmount != 400 {
+		t.Fatal("token account delegate error")
+	}
+	if acc.IsNative == nil || *acc.IsNative != 2039280 {
+		t.Fatal("wrapped sol account error")
+	}
+	if acc.CloseAuthority == nil || !acc.CloseAuthority.Equals(closer) {
+		t.Fatal("close authority error")
+	}
+	if _, err := token.GetMint(client, newTestPublicKey(9), account.PublicKey{}); err == nil {
+		t.Fatal("token account should not be decoded as mint")
+	}
+	if _, err := token.GetAccount(client, newTestPublicKey(9), newTestPublicKey(8)); err == nil {
+		t.Fatal("account owned by other program should fail")
+	}
+}
+
+func Test_DeserializeMultisig(t *testing.T) {
+	data := make([]byte, token.MultisigSize)
+	data[0], data[1], data[2] = 2, 3, 1
+	for i := 0; i < 3; i++ {
+		pk := newTestPublicKey(byte(i + 1))
+		copy(data[3+i*32:], pk[:])
+	}
+	multisig, err := token.DeserializeMultisig(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if multisig.M != 2 || multisig.N != 3 || !multisig.IsInitialized || len(multisig.Signers) != 3 {
+		t.Fatalf("multisig error,got=%+v", multisig)
+	}
+	if !multisig.Signers[2].Equals(newTestPublicKey(3)) {
+		t.Fatal("multisig signer error")
+	}
+	data[1] = 12
+	if _, err := token.DeserializeMultisig(data); err == nil {
+		t.Fatal("too many signers should fail")
+	}
+}
@@ -0,0 +1,206 @@
+package test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/JFJun/solana-go/account"
+	"github.com/JFJun/solana-go/sysvar"
+	"github.com/JFJun/solana-go/token"
+	"github.com/JFJun/solana-go/transaction"
+)
+
+type expectMeta struct {
+	pubkey     account.PublicKey
+	isSigner   bool
+	isWritable bool
+}
+
+func checkInstruction(t *testing.T, name string, ins transaction.ITransactionInstruction, programId account.PublicKey, metas []expectMeta, data string) {
+	t.Helper()
+	if !ins.GetProgramId().Equals(programId) {
+		t.Fatalf("%s program id error,got=%s", name, ins.GetProgramId().String())
+	}
+	keys := ins.GetKeys()
+	if len(keys) != len(metas) {
+		t.Fatalf("%s keys length error,got=%d", name, len(keys))
+	}
+	for i, m := range metas {
+		if !keys[i].PubKey.Equals(m.pubkey) || keys[i].IsSigner != m.isSigner || keys[i].IsWriteable != m.isWritable {
+			t.Fatalf("%s key %d error,got=%+v", name, i, *keys[i])
+		}
+	}
+	expect, err := hex.DecodeString(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(ins.GetData(), expect) {
+		t.Fatalf("%s data error,got=%s", name, hex.EncodeToString(ins.GetData()))
+	}
+}
+
+func Test_TokenTransfer(t *testing.T) {
+	var (
+		source = newTestPublicKey(1)
+		dest   = newTestPublicKey(2)
+		owner  = newTestPublicKey(3)
+		mint   = newTestPublicKey(4)
+	)
+	ins, err := token.NewTransfer(token.TransferParams{Source: source, Destination: dest, Owner: owner, Amount: 1000000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "transfer", ins, token.ProgramId, []expectMeta{
+		{source, false, true}, {dest, false, true}, {owner, true, false},
+	}, "0340420f0000000000")
+
+	ins, err = token.NewTransferChecked(token.TransferCheckedParams{
+		Source: source, Mint: mint, Destination: dest, Owner: owner, Amount: 1000000, Decimals: 6,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "transfer checked", ins, token.ProgramId, []expectMeta{
+		{source, false, true}, {mint, false, false}, {dest, false, true}, {owner, true, false},
+	}, "0c40420f000000000006")
+}
+
+func Test_TokenMultisig(t *testing.T) {
+	var (
+		source   = newTestPublicKey(1)
+		dest     = newTestPublicKey(2)
+		multisig = newTestPublicKey(3)
+		signer1  = newTestPublicKey(5)
+		signer2  = newTestPublicKey(6)
+	)
+	ins, err := token.NewTransfer(token.TransferParams{
+		Source: source, Destination: dest, Owner: multisig, Amount: 1,
+		MultiSigners: []account.PublicKey{signer1, signer2},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "multisig transfer", ins, token.ProgramId, []expectMeta{
+		{source, false, true}, {dest, false, true}, {multisig, false, false}, {signer1, true, false}, {signer2, true, false},
+	}, "030100000000000000")
+
+	ins, err = token.NewInitializeMultisig(token.InitializeMultisigParams{
+		Multisig: multisig, Signers: []account.PublicKey{signer1, signer2}, M: 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "initialize multisig", ins, token.ProgramId, []expectMeta{
+		{multisig, false, true}, {sysvar.RentPubkey, false, false}, {signer1, false, false}, {signer2, false, false},
+	}, "0202")
+	if _, err := token.NewInitializeMultisig(token.InitializeMultisigParams{
+		Multisig: multisig, Signers: []account.PublicKey{signer1}, M: 2,
+	}); err == nil {
+		t.Fatal("m greater than signers should fail")
+	}
+}
+
+func Test_TokenInitialize(t *testing.T) {
+	var (
+		mint      = newTestPublicKey(1)
+		authority = newTestPublicKey(2)
+		tokenAcc  = newTestPublicKey(3)
+	)
+	ins, err := token.NewInitializeMint(token.InitializeMintParams{Mint: mint, Decimals: 9, MintAuthority: authority, FreezeAuthority: &authority})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "initialize mint", ins, token.ProgramId, []expectMeta{
+		{mint, false, true}, {sysvar.RentPubkey, false, false},
+	}, "0009"+hex.EncodeToString(authority[:])+"01"+hex.EncodeToString(authority[:]))
+
+	ins, err = token.NewInitializeMint2(token.InitializeMintParams{Mint: mint, Decimals: 6, MintAuthority: authority})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "initialize mint2", ins, token.ProgramId, []expectMeta{
+		{mint, false, true},
+	}, "1406"+hex.EncodeToString(authority[:])+"00"+hex.EncodeToString(make([]byte, 32)))
+
+	ins, err = token.NewInitializeAccount(token.InitializeAccountParams{Account: tokenAcc, Mint: mint, Owner: authority})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "initialize account", ins, token.ProgramId, []expectMeta{
+		{tokenAcc, false, true}, {mint, false, false}, {authority, false, false}, {sysvar.RentPubkey, false, false},
+	}, "01")
+
+	ins, err = token.NewInitializeAccount3(token.InitializeAccountParams{Account: tokenAcc, Mint: mint, Owner: authority})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "initialize account3", ins, token.ProgramId, []expectMeta{
+		{tokenAcc, false, true}, {mint, false, false},
+	}, "12"+hex.EncodeToString(authority[:]))
+}
+
+func Test_TokenAuthorityInstructions(t *testing.T) {
+	var (
+		tokenAcc  = newTestPublicKey(1)
+		mint      = newTestPublicKey(2)
+		authority = newTestPublicKey(3)
+		other     = newTestPublicKey(4)
+	)
+	cases := []struct {
+		name  string
+		build func() (transaction.ITransactionInstruction, error)
+		metas []expectMeta
+		data  string
+	}{
+		{"mint to", func() (transaction.ITransactionInstruction, error) {
+			return token.NewMintTo(token.MintToParams{Mint: mint, Destination: tokenAcc, Authority: authority, Amount: 5})
+		}, []expectMeta{{mint, false, true}, {tokenAcc, false, true}, {authority, true, false}}, "070500000000000000"},
+		{"burn", func() (transaction.ITransactionInstruction, error) {
+			return token.NewBurn(token.BurnParams{Account: tokenAcc, Mint: mint, Owner: authority, Amount: 5})
+		}, []expectMeta{{tokenAcc, false, true}, {mint, false, true}, {authority, true, false}}, "080500000000000000"},
+		{"approve", func() (transaction.ITransactionInstruction, error) {
+			return token.NewApprove(token.ApproveParams{Source: tokenAcc, Delegate: other, Owner: authority, Amount: 5})
+		}, []expectMeta{{tokenAcc, false, true}, {other, false, false}, {authority, true, false}}, "040500000000000000"},
+		{"revoke", func() (transaction.ITransactionInstruction, error) {
+			return token.NewRevoke(token.RevokeParams{Source: tokenAcc, Owner: authority})
+		}, []expectMeta{{tokenAcc, false, true}, {authority, true, false}}, "05"},
+		{"set authority", func() (transaction.ITransactionInstruction, error) {
+			return token.NewSetAuthority(token.SetAuthorityParams{Account: tokenAcc, CurrentAuthority: authority,
+				AuthorityType: token.AuthorityCloseAccount, NewAuthority: &other})
+		}, []expectMeta{{tokenAcc, false, true}, {authority, true, false}}, "060301" + hex.EncodeToString(other[:])},
+		{"close account", func() (transaction.ITransactionInstruction, error) {
+			return token.NewCloseAccount(token.CloseAccountParams{Account: tokenAcc, Destination: other, Owner: authority})
+		}, []expectMeta{{tokenAcc, false, true}, {other, false, true}, {authority, true, false}}, "09"},
+		{"freeze account", func() (transaction.ITransactionInstruction, error) {
+			return token.NewFreezeAccount(token.FreezeAccountParams{Account: tokenAcc, Mint: mint, Authority: authority})
+		}, []expectMeta{{tokenAcc, false, true}, {mint, false, false}, {authority, true, false}}, "0a"},
+		{"thaw account", func() (transaction.ITransactionInstruction, error) {
+			return token.NewThawAccount(token.ThawAccountParams{Account: tokenAcc, Mint: mint, Authority: authority})
+		}, []expectMeta{{tokenAcc, false, true}, {mint, false, false}, {authority, true, false}}, "0b"},
+		{"sync native", func() (transaction.ITransactionInstruction, error) {
+			return token.NewSyncNative(token.SyncNativeParams{Account: tokenAcc})
+		}, []expectMeta{{tokenAcc, false, true}}, "11"},
+	}
+	for _, c := range cases {
+		ins, err := c.build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkInstruction(t, c.name, ins, token.ProgramId, c.metas, c.data)
+	}
+	if _, err := token.NewSetAuthority(token.SetAuthorityParams{Account: tokenAcc, CurrentAuthority: authority, AuthorityType: 4}); err == nil {
+		t.Fatal("invalid authority type should fail")
+	}
+}
+
+func Test_TokenCustomProgramId(t *testing.T) {
+	programId := newTestPublicKey(9)
+	ins, err := token.NewSyncNative(token.SyncNativeParams{Account: newTestPublicKey(1), ProgramId: programId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ins.GetProgramId().Equals(programId) {
+		t.Fatal("custom program id error")
+	}
+}
@@ -30,8 +30,8 @@ func Test_TransferTest(t *testing.T) {
 	account1 := account.NewAccountBySecret(a1[:32])
 	account2 := account.NewAccountBySecret(a2[:32])
 	tp := transaction.TransferParams{
-		From:   account1.ToBase58(),
-		To:     account2.ToBase58(),
+		From:   account1.GetPublicKey(),
+		To:     account2.GetPublicKey(),
 		Amount: big.NewInt(100000000),
 	}
 	transfer, err := transaction.NewTransfer(tp)
@@ -39,8 +39,8 @@ func Test_TransferTest(t *testing.T) {
 		panic(err)
 	}
 	tp2 := transaction.TransferParams{
-		From:   account2.ToBase58(),
-		To:     account1.ToBase58(),
+		From:   account2.GetPublicKey(),
+		To:     account1.GetPublicKey(),
 		Amount: big.NewInt(123),
 	}
 	transfer2, err := transaction.NewTransfer(tp2)
@@ -59,16 +59,22 @@ func Test_TransferTest(t *testing.T) {
 		panic(err)
 	}
 	fmt.Println(tx.Signatures[0].Signature)
-	fmt.Println(hex.EncodeToString(tx.Signatures[0].PublicKey))
+	fmt.Println(hex.EncodeToString(tx.Signatures[0].PublicKey[:]))
 	fmt.Println(tx.Signatures[1].Signature)
-	fmt.Println(hex.EncodeToString(tx.Signatures[1].PublicKey))
+	fmt.Println(hex.EncodeToString(tx.Signatures[1].PublicKey[:]))
 }
 
 func Test_Transfer(t *testing.T) {
 	url := "http://sol.rylink.io:28899"
 	client := rpc.New(url, "", "")
-	from := "9SvsEyncSPjZaqjEsGjfvgaQowxq1BTNTJo6imGxseyx"
-	to := "BHUNqtk5Vv6vfQTxpPjqWo2v8GPZJbqBonCaqhhK1Hub"
+	from, err := account.PublicKeyFromBase58("9SvsEyncSPjZaqjEsGjfvgaQowxq1BTNTJo6imGxseyx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	to, err := account.PublicKeyFromBase58("BHUNqtk5Vv6vfQTxpPjqWo2v8GPZJbqBonCaqhhK1Hub")
+	if err != nil {
+		t.Fatal(err)
+	}
 	// 构建交易参数
 	tp := transaction.TransferParams{
 		From:   from,
@@ -0,0 +1,104 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/JFJun/solana-go/account"
+	"github.com/JFJun/solana-go/transaction"
+)
+
+func Test_VerifyMessage(t *testing.T) {
+	acc, err := account.NewAccount()
+	if err != nil {
+		t.Fatal(err)
+	}
+	message := []byte("hello solana")
+	sig := acc.Sign(message)
+	if !account.VerifyMessage(acc.GetPublicKey(), message, sig) {
+		t.Fatal("verify message error")
+	}
+	if account.VerifyMessage(acc.GetPublicKey(), []byte("hello solana!"), sig) {
+		t.Fatal("verify tampered message should fail")
+	}
+	if account.VerifyMessage(acc.GetPublicKey(), message, sig[:63]) {
+		t.Fatal("verify short signature should fail")
+	}
+}
+
+func Test_VerifySignatures(t *testing.T) {
+	tx, account1, account2 := newTwoSignerTx(t)
+	if err := tx.PartialSign([]*account.Account{account1}); err != nil {
+		t.Fatal(err)
+	}
+	results, err := tx.VerifySignatures()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("verify results length error,got=%d", len(results))
+	}
+	for _, r := range results {
+		switch r.PublicKey {
+		case account1.GetPublicKey():
+			if !r.Signed || !r.Valid {
+				t.Fatal("account1 signature should be valid")
+			}
+		case account2.GetPublicKey():
+			if r.Signed || r.Valid {
+				t.Fatal("account2 should not be signed")
+			}
+		default:
+			t.Fatalf("unexpected signer [%s]", r.PublicKey.String())
+		}
+	}
+
+	// 外部签名者签错了数据
+	if err := tx.AddSignature(account2.GetPublicKey(), account2.Sign([]byte("wrong message"))); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.Serialize(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.SerializeWithConfig(transaction.SerializeConfig{
+		RequireAllSignatures: true,
+		VerifySignatures:     true,
+	}); err == nil {
+		t.Fatal("serialize with invalid signature should fail")
+	}
+	if err := tx.PartialSign([]*account.Account{account2}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.SerializeWithConfig(transaction.SerializeConfig{
+		RequireAllSignatures: true,
+		VerifySignatures:     true,
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_VerifyDeserializedSignatures(t *testing.T) {
+	account1, account2 := newTestAccounts()
+	signers := []*account.Account{account1, account2}
+	decoded, err := transaction.DeserializeTransaction(newForeignWireTx(newForeignMessage(signers...), signers, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err := decoded.VerifySignatures()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("verify results length error,got=%d", len(results))
+	}
+	for i, r := range results {
+		if r.PublicKey != signers[i].GetPublicKey() || !r.Signed || !r.Valid {
+			t.Fatalf("signature [%d] should be valid", i)
+		}
+	}
+	if _, err := decoded.SerializeWithConfig(transaction.SerializeConfig{
+		RequireAllSignatures: true,
+		VerifySignatures:     true,
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,175 @@
+package test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"testing"
+
+	"github.com/JFJun/solana-go/account"
+	"github.com/JFJun/solana-go/sysvar"
+	"github.com/JFJun/solana-go/vote"
+)
+
+type bincodeWriter struct {
+	bytes.Buffer
+}
+
+func (w *bincodeWriter) u8(v uint8) { w.WriteByte(v) }
+func (w *bincodeWriter) u32(v uint32) {
+	binary.Write(w, binary.LittleEndian, v)
+}
+func (w *bincodeWriter) u64(v uint64) {
+	binary.Write(w, binary.LittleEndian, v)
+}
+func (w *bincodeWriter) pubkey(pk account.PublicKey) { w.Write(pk[:]) }
+
+func newVoteAccountData(version uint32, node, withdrawer, voter account.PublicKey) []byte {
+	w := new(bincodeWriter)
+	w.u32(version)
+	w.pubkey(node)
+	if version == vote.VoteStateVersionV0_23_5 {
+		w.pubkey(voter)
+		w.u64(100)
+		for i := 0; i < 32; i++ {
+			w.Write(make([]byte, 32+24))
+		}
+		w.u64(31)
+	}
+	w.pubkey(withdrawer)
+	w.u8(7)
+	// votes
+	w.u64(2)
+	for i, slot := range []uint64{1000, 1001} {
+		if version == vote.VoteStateVersionCurrent {
+			w.u8(uint8(i + 1))
+		}
+		w.u64(slot)
+		w.u32(uint32(2 - i))
+	}
+	// root slot
+	w.u8(1)
+	w.u64(968)
+	if version != vote.VoteStateVersionV0_23_5 {
+		// authorized voters
+		w.u64(2)
+		w.u64(100)
+		w.pubkey(voter)
+		w.u64(105)
+		w.pubkey(node)
+		// prior voters：只写入了第0个位置
+		w.pubkey(withdrawer)
+		w.u64(90)
+		w.u64(100)
+		for i := 1; i < 32; i++ {
+			w.Write(make([]byte, 32+16))
+		}
+		w.u64(0)
+		w.u8(0)
+	}
+	// epoch credits
+	w.u64(2)
+	for _, c := range [][3]uint64{{101, 5000, 1000}, {102, 9000, 5000}} {
+		w.u64(c[0])
+		w.u64(c[1])
+		w.u64(c[2])
+	}
+	w.u64(1001)
+	w.u64(1600000000)
+	// 账户数据有预留空间
+	w.Write(make([]byte, 64))
+	return w.Bytes()
+}
+
+func Test_DeserializeVoteAccount(t *testing.T) {
+	node, withdrawer, voter := newTestPublicKey(1), newTestPublicKey(2), newTestPublicKey(3)
+	for _, version := range []uint32{vote.VoteStateVersionV0_23_5, vote.VoteStateVersionV1_14_11, vote.VoteStateVersionCurrent} {
+		va, err := vote.DeserializeVoteAccount(newVoteAccountData(version, node, withdrawer, voter))
+		if err != nil {
+			t.Fatalf("version %d: %v", version, err)
+		}
+		if !va.NodePubkey.Equals(node) || !va.AuthorizedWithdrawer.Equals(withdrawer) || va.Commission != 7 {
+			t.Fatalf("version %d: vote account error,got=%+v", version, va)
+		}
+		if slot, ok := va.LastVotedSlot(); !ok || slot != 1001 || va.Votes[0].ConfirmationCount != 2 {
+			t.Fatalf("version %d: votes error,got=%+v", version, va.Votes)
+		}
+		if va.RootSlot == nil || *va.RootSlot != 968 {
+			t.Fatalf("version %d: root slot error", version)
+		}
+		if v, ok := va.AuthorizedVoter(102); !ok || !v.Equals(voter) {
+			t.Fatalf("version %d: authorized voter error", version)
+		}
+		if credits, ok := va.EpochCreditsEarned(102); !ok || credits != 4000 {
+			t.Fatalf("version %d: epoch credits error", version)
+		}
+		if va.LastTimestamp.Slot != 1001 || va.LastTimestamp.Timestamp != 1600000000 {
+			t.Fatalf("version %d: last timestamp error", version)
+		}
+		switch version {
+		case vote.VoteStateVersionCurrent:
+			if va.Votes[1].Latency != 2 {
+				t.Fatal("landed vote latency error")
+			}
+			fallthrough
+		case vote.VoteStateVersionV1_14_11:
+			if v, ok := va.AuthorizedVoter(106); !ok || !v.Equals(node) {
+				t.Fatal("authorized voter change error")
+			}
+			if len(va.PriorVoters) != 1 || !va.PriorVoters[0].Pubkey.Equals(withdrawer) || va.PriorVoters[0].EpochEnd != 100 {
+				t.Fatalf("prior voters error,got=%+v", va.PriorVoters)
+			}
+		default:
+			if len(va.PriorVoters) != 0 {
+				t.Fatal("prior voters should be empty")
+			}
+		}
+	}
+	data := newVoteAccountData(vote.VoteStateVersionCurrent, node, withdrawer, voter)
+	if _, err := vote.DeserializeVoteAccount(data[:100]); err == nil {
+		t.Fatal("truncated vote account should fail")
+	}
+	binary.LittleEndian.PutUint32(data[0:4], 9)
+	if _, err := vote.DeserializeVoteAccount(data); err == nil {
+		t.Fatal("unknown version should fail")
+	}
+}
+
+func Test_VoteInstructions(t *testing.T) {
+	voteKey, authority, to := newTestPublicKey(1), newTestPublicKey(2), newTestPublicKey(3)
+	ins, err := vote.NewWithdraw(vote.WithdrawParams{VotePubkey: voteKey, AuthorizedPubkey: authority, ToPubkey: to, Lamports: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "withdraw", ins, vote.ProgramId, []expectMeta{
+		{voteKey, false, true}, {to, false, true}, {authority, true, false},
+	}, "03000000e803000000000000")
+
+	ins, err = vote.NewUpdateCommission(vote.UpdateCommissionParams{VotePubkey: voteKey, AuthorizedPubkey: authority, Commission: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "update commission", ins, vote.ProgramId, []expectMeta{
+		{voteKey, false, true}, {authority, true, false},
+	}, "050000000a")
+	if _, err := vote.NewUpdateCommission(vote.UpdateCommissionParams{Commission: 101}); err == nil {
+		t.Fatal("commission greater than 100 should fail")
+	}
+
+	ins, err = vote.NewUpdateValidatorIdentity(vote.UpdateValidatorIdentityParams{VotePubkey: voteKey, AuthorizedPubkey: authority, NodePubkey: to})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "update validator identity", ins, vote.ProgramId, []expectMeta{
+		{voteKey, false, true}, {to, true, false}, {authority, true, false},
+	}, "04000000")
+
+	ins, err = vote.NewAuthorize(vote.AuthorizeParams{VotePubkey: voteKey, AuthorizedPubkey: authority, NewAuthorized: to,
+		VoteAuthorize: vote.VoteAuthorizeWithdrawer})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkInstruction(t, "authorize", ins, vote.ProgramId, []expectMeta{
+		{voteKey, false, true}, {sysvar.ClockPubkey, false, false}, {authority, true, false},
+	}, "01000000"+hex.EncodeToString(to[:])+"01000000")
+}
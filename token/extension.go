@@ -0,0 +1,415 @@
+package token
+
+/*
+func：Token-2022扩展数据（TLV）的解析
+author： flynn
+date: 2020-08-03
+fork: https://github.com/solana-labs/solana-program-library/token/js/src/extensions
+*/
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/JFJun/solana-go/account"
+	"math/bits"
+)
+
+/*
+TLV：u16 type | u16 length | value
+*/
+const (
+	tlvHeaderSize     = 4
+	extensionTypeSize = 2
+)
+
+type Extension struct {
+	Type ExtensionType
+	Data []byte
+}
+
+type Extensions []Extension
+
+/*
+查找扩展的原始数据
+*/
+func (exts Extensions) Get(extType ExtensionType) ([]byte, bool) {
+	for _, ext := range exts {
+		if ext.Type == extType {
+			return ext.Data, true
+		}
+	}
+	return nil, false
+}
+
+func (exts Extensions) Has(extType ExtensionType) bool {
+	_, ok := exts.Get(extType)
+	return ok
+}
+
+/*
+以下方法在扩展不存在时返回nil, nil
+*/
+func (exts Extensions) TransferFeeConfig() (*TransferFeeConfig, error) {
+	data, ok := exts.Get(ExtensionTransferFeeConfig)
+	if !ok {
+		return nil, nil
+	}
+	return DeserializeTransferFeeConfig(data)
+}
+
+func (exts Extensions) TransferFeeAmount() (*TransferFeeAmount, error) {
+	data, ok := exts.Get(ExtensionTransferFeeAmount)
+	if !ok {
+		return nil, nil
+	}
+	if err := checkExtensionLength(ExtensionTransferFeeAmount, data); err != nil {
+		return nil, err
+	}
+	return &TransferFeeAmount{WithheldAmount: binary.LittleEndian.Uint64(data)}, nil
+}
+
+func (exts Extensions) MintCloseAuthority() (*MintCloseAuthority, error) {
+	data, ok := exts.Get(ExtensionMintCloseAuthority)
+	if !ok {
+		return nil, nil
+	}
+	if err := checkExtensionLength(ExtensionMintCloseAuthority, data); err != nil {
+		return nil, err
+	}
+	return &MintCloseAuthority{CloseAuthority: readOptionalNonZeroPublicKey(data)}, nil
+}
+
+func (exts Extensions) DefaultAccountState() (*DefaultAccountState, error) {
+	data, ok := exts.Get(ExtensionDefaultAccountState)
+	if !ok {
+		return nil, nil
+	}
+	if err := checkExtensionLength(ExtensionDefaultAccountState, data); err != nil {
+		return nil, err
+	}
+	if AccountState(data[0]) > AccountStateFrozen {
+		return nil, fmt.Errorf("default account state [%d] is not valid", data[0])
+	}
+	return &DefaultAccountState{State: AccountState(data[0])}, nil
+}
+
+func (exts Extensions) InterestBearingConfig() (*InterestBearingConfig, error) {
+	data, ok := exts.Get(ExtensionInterestBearingConfig)
+	if !ok {
+		return nil, nil
+	}
+	if err := checkExtensionLength(ExtensionInterestBearingConfig, data); err != nil {
+		return nil, err
+	}
+	return &InterestBearingConfig{
+		RateAuthority:           readOptionalNonZeroPublicKey(data[0:32]),
+		InitializationTimestamp: int64(binary.LittleEndian.Uint64(data[32:40])),
+		PreUpdateAverageRate:    int16(binary.LittleEndian.Uint16(data[40:42])),
+		LastUpdateTimestamp:     int64(binary.LittleEndian.Uint64(data[42:50])),
+		CurrentRate:             int16(binary.LittleEndian.Uint16(data[50:52])),
+	}, nil
+}
+
+func (exts Extensions) TransferHook() (*TransferHook, error) {
+	data, ok := exts.Get(ExtensionTransferHook)
+	if !ok {
+		return nil, nil
+	}
+	if err := checkExtensionLength(ExtensionTransferHook, data); err != nil {
+		return nil, err
+	}
+	return &TransferHook{
+		Authority: readOptionalNonZeroPublicKey(data[0:32]),
+		ProgramId: readOptionalNonZeroPublicKey(data[32:64]),
+	}, nil
+}
+
+func (exts Extensions) MetadataPointer() (*MetadataPointer, error) {
+	data, ok := exts.Get(ExtensionMetadataPointer)
+	if !ok {
+		return nil, nil
+	}
+	if err := checkExtensionLength(ExtensionMetadataPointer, data); err != nil {
+		return nil, err
+	}
+	return &MetadataPointer{
+		Authority:       readOptionalNonZeroPublicKey(data[0:32]),
+		MetadataAddress: readOptionalNonZeroPublicKey(data[32:64]),
+	}, nil
+}
+
+func (exts Extensions) TokenMetadata() (*TokenMetadata, error) {
+	data, ok := exts.Get(ExtensionTokenMetadata)
+	if !ok {
+		return nil, nil
+	}
+	return DeserializeTokenMetadata(data)
+}
+
+func (exts Extensions) PermanentDelegate() (*account.PublicKey, error) {
+	data, ok := exts.Get(ExtensionPermanentDelegate)
+	if !ok {
+		return nil, nil
+	}
+	if err := checkExtensionLength(ExtensionPermanentDelegate, data); err != nil {
+		return nil, err
+	}
+	return readOptionalNonZeroPublicKey(data), nil
+}
+
+/*
+账户的owner不能被修改，ATA默认带有该扩展
+*/
+func (exts Extensions) IsImmutableOwner() bool {
+	return exts.Has(ExtensionImmutableOwner)
+}
+
+/*
+转入该账户的交易必须带memo
+*/
+func (exts Extensions) IsMemoRequired() bool {
+	data, ok := exts.Get(ExtensionMemoTransfer)
+	return ok && len(data) == 1 && data[0] == 1
+}
+
+func (exts Extensions) IsNonTransferable() bool {
+	return exts.Has(ExtensionNonTransferable)
+}
+
+type TransferFee struct {
+	Epoch                  uint64
+	MaximumFee             uint64
+	TransferFeeBasisPoints uint16
+}
+
+/*
+手续费 = ceil(amount * bps / 10000)，不超过MaximumFee
+*/
+func (fee *TransferFee) CalculateFee(amount uint64) uint64 {
+	if fee.TransferFeeBasisPoints == 0 || amount == 0 {
+		return 0
+	}
+	// 128位乘法避免溢出，向上取整
+	hi, lo := bits.Mul64(amount, uint64(fee.TransferFeeBasisPoints))
+	lo, carry := bits.Add64(lo, 9999, 0)
+	hi += carry
+	if hi >= 10000 {
+		// 结果超过u64
+		return fee.MaximumFee
+	}
+	result, _ := bits.Div64(hi, lo, 10000)
+	if result > fee.MaximumFee {
+		return fee.MaximumFee
+	}
+	return result
+}
+
+type TransferFeeConfig struct {
+	TransferFeeConfigAuthority *account.PublicKey
+	WithdrawWithheldAuthority  *account.PublicKey
+	WithheldAmount             uint64
+	OlderTransferFee           TransferFee
+	NewerTransferFee           TransferFee
+}
+
+/*
+newer费率从其epoch开始生效，之前使用older费率
+*/
+func (config *TransferFeeConfig) GetEpochFee(epoch uint64) *TransferFee {
+	if epoch >= config.NewerTransferFee.Epoch {
+		return &config.NewerTransferFee
+	}
+	return &config.OlderTransferFee
+}
+
+func (config *TransferFeeConfig) CalculateEpochFee(epoch, amount uint64) uint64 {
+	return config.GetEpochFee(epoch).CalculateFee(amount)
+}
+
+type TransferFeeAmount struct {
+	WithheldAmount uint64
+}
+
+type MintCloseAuthority struct {
+	CloseAuthority *account.PublicKey
+}
+
+type DefaultAccountState struct {
+	State AccountState
+}
+
+/*
+利率单位为基点，时间戳为unix秒
+*/
+type InterestBearingConfig struct {
+	RateAuthority           *account.PublicKey
+	InitializationTimestamp int64
+	PreUpdateAverageRate    int16
+	LastUpdateTimestamp     int64
+	CurrentRate             int16
+}
+
+type TransferHook struct {
+	Authority *account.PublicKey
+	ProgramId *account.PublicKey
+}
+
+type MetadataPointer struct {
+	Authority       *account.PublicKey
+	MetadataAddress *account.PublicKey
+}
+
+type TokenMetadata struct {
+	UpdateAuthority    *account.PublicKey
+	Mint               account.PublicKey
+	Name               string
+	Symbol             string
+	Uri                string
+	AdditionalMetadata [][2]string
+}
+
+func DeserializeTransferFeeConfig(data []byte) (*TransferFeeConfig, error) {
+	if err := checkExtensionLength(ExtensionTransferFeeConfig, data); err != nil {
+		return nil, err
+	}
+	return &TransferFeeConfig{
+		TransferFeeConfigAuthority: readOptionalNonZeroPublicKey(data[0:32]),
+		WithdrawWithheldAuthority:  readOptionalNonZeroPublicKey(data[32:64]),
+		WithheldAmount:             binary.LittleEndian.Uint64(data[64:72]),
+		OlderTransferFee:           readTransferFee(data[72:90]),
+		NewerTransferFee:           readTransferFee(data[90:108]),
+	}, nil
+}
+
+/*
+TokenMetadata为borsh编码：update authority | mint | string name | string symbol | string uri | vec<(string,string)>
+*/
+func DeserializeTokenMetadata(data []byte) (*TokenMetadata, error) {
+	if len(data) < 64 {
+		return nil, fmt.Errorf("token metadata length is %d, less than 64", len(data))
+	}
+	md := &TokenMetadata{UpdateAuthority: readOptionalNonZeroPublicKey(data[0:32])}
+	copy(md.Mint[:], data[32:64])
+	rest := data[64:]
+	var err error
+	for _, field := range []*string{&md.Name, &md.Symbol, &md.Uri} {
+		if *field, rest, err = readBorshString(rest); err != nil {
+			return nil, err
+		}
+	}
+	if len(rest) < 4 {
+		return nil, errors.New("token metadata additional metadata is missing")
+	}
+	count := binary.LittleEndian.Uint32(rest)
+	rest = rest[4:]
+	for i := uint32(0); i < count; i++ {
+		var kv [2]string
+		if kv[0], rest, err = readBorshString(rest); err != nil {
+			return nil, err
+		}
+		if kv[1], rest, err = readBorshString(rest); err != nil {
+			return nil, err
+		}
+		md.AdditionalMetadata = append(md.AdditionalMetadata, kv)
+	}
+	return md, nil
+}
+
+/*
+解析基础数据之后的扩展：accountType位于AccountSize处，之后为TLV列表
+*/
+func parseExtensions(data []byte, accountType uint8) (Extensions, error) {
+	if len(data) <= AccountSize || len(data) == MultisigSize {
+		return nil, fmt.Errorf("extension data length %d is not valid", len(data))
+	}
+	if data[AccountSize] != accountType {
+		return nil, fmt.Errorf("account type is %d, not %d", data[AccountSize], accountType)
+	}
+	var exts Extensions
+	rest := data[AccountSize+1:]
+	for len(rest) >= tlvHeaderSize {
+		extType := ExtensionType(binary.LittleEndian.Uint16(rest[0:2]))
+		if extType == ExtensionUninitialized {
+			break
+		}
+		length := int(binary.LittleEndian.Uint16(rest[2:4]))
+		if len(rest) < tlvHeaderSize+length {
+			return nil, fmt.Errorf("extension [%d] length %d is out of range", extType, length)
+		}
+		exts = append(exts, Extension{Type: extType, Data: rest[tlvHeaderSize : tlvHeaderSize+length]})
+		rest = rest[tlvHeaderSize+length:]
+	}
+	return exts, nil
+}
+
+/*
+计算带有定长扩展的mint或token账户需要的空间，用于CreateAccount的Space
+变长扩展（如TokenMetadata）通过variableLength另外传入内容的长度，不包含TLV头，与web3.js的getMintLen一致
+*/
+func GetMintLen(extTypes []ExtensionType, variableLength int) (uint64, error) {
+	return getLen(MintSize, extTypes, variableLength)
+}
+
+func GetAccountLen(extTypes []ExtensionType) (uint64, error) {
+	return getLen(AccountSize, extTypes, 0)
+}
+
+func getLen(baseSize int, extTypes []ExtensionType, variableLength int) (uint64, error) {
+	if len(extTypes) == 0 && variableLength == 0 {
+		return uint64(baseSize), nil
+	}
+	size := AccountSize + 1
+	if variableLength > 0 {
+		size += tlvHeaderSize + variableLength
+	}
+	for _, extType := range extTypes {
+		length, ok := extensionLength[extType]
+		if !ok {
+			return 0, fmt.Errorf("extension [%d] is not fixed length", extType)
+		}
+		size += tlvHeaderSize + length
+	}
+	// 避免与多签账户长度相同，与adjust_len_for_multisig一致只多加一个ExtensionType的长度
+	if size == MultisigSize {
+		size += extensionTypeSize
+	}
+	return uint64(size), nil
+}
+
+func checkExtensionLength(extType ExtensionType, data []byte) error {
+	if len(data) != extensionLength[extType] {
+		return fmt.Errorf("extension [%d] length is %d, not equal %d", extType, len(data), extensionLength[extType])
+	}
+	return nil
+}
+
+/*
+扩展中的OptionalNonZeroPubkey：全0表示None
+*/
+func readOptionalNonZeroPublicKey(data []byte) *account.PublicKey {
+	var pubkey account.PublicKey
+	copy(pubkey[:], data[:account.PublicKeySize])
+	if pubkey.IsZero() {
+		return nil
+	}
+	return &pubkey
+}
+
+func readTransferFee(data []byte) TransferFee {
+	return TransferFee{
+		Epoch:                  binary.LittleEndian.Uint64(data[0:8]),
+		MaximumFee:             binary.LittleEndian.Uint64(data[8:16]),
+		TransferFeeBasisPoints: binary.LittleEndian.Uint16(data[16:18]),
+	}
+}
+
+func readBorshString(data []byte) (string, []byte, error) {
+	if len(data) < 4 {
+		return "", nil, errors.New("borsh string length is missing")
+	}
+	length := binary.LittleEndian.Uint32(data)
+	if uint64(len(data)-4) < uint64(length) {
+		return "", nil, fmt.Errorf("borsh string length %d is out of range", length)
+	}
+	return string(data[4 : 4+length]), data[4+length:], nil
+}
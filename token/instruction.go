@@ -0,0 +1,358 @@
+package token
+
+/*
+func：
+author： flynn
+date: 2020-08-03
+fork: https://github.com/solana-labs/solana-program-library/token/js/src/instructions
+*/
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"github.com/JFJun/solana-go/account"
+	"github.com/JFJun/solana-go/sysvar"
+	"github.com/JFJun/solana-go/transaction"
+)
+
+/*
+所有参数中：
+ProgramId为空时使用spl token program，Token-2022等兼容程序可以传入自己的id
+MultiSigners不为空时，Owner/Authority为多签账户（不签名），由MultiSigners签名
+*/
+
+type InitializeMintParams struct {
+	Mint            account.PublicKey
+	Decimals        uint8
+	MintAuthority   account.PublicKey
+	FreezeAuthority *account.PublicKey // 为空时不能冻结账户
+	ProgramId       account.PublicKey
+}
+
+type InitializeAccountParams struct {
+	Account   account.PublicKey
+	Mint      account.PublicKey
+	Owner     account.PublicKey
+	ProgramId account.PublicKey
+}
+
+type InitializeMultisigParams struct {
+	Multisig  account.PublicKey
+	Signers   []account.PublicKey
+	M         uint8 // 需要的签名数量
+	ProgramId account.PublicKey
+}
+
+type TransferParams struct {
+	Source       account.PublicKey
+	Destination  account.PublicKey
+	Owner        account.PublicKey
+	Amount       uint64
+	MultiSigners []account.PublicKey
+	ProgramId    account.PublicKey
+}
+
+type TransferCheckedParams struct {
+	Source       account.PublicKey
+	Mint         account.PublicKey
+	Destination  account.PublicKey
+	Owner        account.PublicKey
+	Amount       uint64
+	Decimals     uint8
+	MultiSigners []account.PublicKey
+	ProgramId    account.PublicKey
+}
+
+type MintToParams struct {
+	Mint         account.PublicKey
+	Destination  account.PublicKey
+	Authority    account.PublicKey
+	Amount       uint64
+	MultiSigners []account.PublicKey
+	ProgramId    account.PublicKey
+}
+
+type BurnParams struct {
+	Account      account.PublicKey
+	Mint         account.PublicKey
+	Owner        account.PublicKey
+	Amount       uint64
+	MultiSigners []account.PublicKey
+	ProgramId    account.PublicKey
+}
+
+type ApproveParams struct {
+	Source       account.PublicKey
+	Delegate     account.PublicKey
+	Owner        account.PublicKey
+	Amount       uint64
+	MultiSigners []account.PublicKey
+	ProgramId    account.PublicKey
+}
+
+type RevokeParams struct {
+	Source       account.PublicKey
+	Owner        account.PublicKey
+	MultiSigners []account.PublicKey
+	ProgramId    account.PublicKey
+}
+
+type SetAuthorityParams struct {
+	Account          account.PublicKey // mint或token账户
+	CurrentAuthority account.PublicKey
+	AuthorityType    AuthorityType
+	NewAuthority     *account.PublicKey // 为空时移除该权限
+	MultiSigners     []account.PublicKey
+	ProgramId        account.PublicKey
+}
+
+type CloseAccountParams struct {
+	Account      account.PublicKey
+	Destination  account.PublicKey
+	Owner        account.PublicKey
+	MultiSigners []account.PublicKey
+	ProgramId    account.PublicKey
+}
+
+type FreezeAccountParams struct {
+	Account      account.PublicKey
+	Mint         account.PublicKey
+	Authority    account.PublicKey
+	MultiSigners []account.PublicKey
+	ProgramId    account.PublicKey
+}
+
+type ThawAccountParams struct {
+	Account      account.PublicKey
+	Mint         account.PublicKey
+	Authority    account.PublicKey
+	MultiSigners []account.PublicKey
+	ProgramId    account.PublicKey
+}
+
+type SyncNativeParams struct {
+	Account   account.PublicKey
+	ProgramId account.PublicKey
+}
+
+func NewInitializeMint(params InitializeMintParams) (transaction.ITransactionInstruction, error) {
+	return newTokenInstruction(params.ProgramId, []*transaction.AccountMeta{
+		transaction.NewAccountMeta(params.Mint, false, true),
+		transaction.NewAccountMeta(sysvar.RentPubkey, false, false),
+	}, encodeInitializeMint(InstructionInitializeMint, params))
+}
+
+/*
+不需要rent sysvar账户
+*/
+func NewInitializeMint2(params InitializeMintParams) (transaction.ITransactionInstruction, error) {
+	return newTokenInstruction(params.ProgramId, []*transaction.AccountMeta{
+		transaction.NewAccountMeta(params.Mint, false, true),
+	}, encodeInitializeMint(InstructionInitializeMint2, params))
+}
+
+func encodeInitializeMint(index uint8, params InitializeMintParams) []byte {
+	buf := new(bytes.Buffer)
+	buf.WriteByte(index)
+	buf.WriteByte(params.Decimals)
+	buf.Write(params.MintAuthority[:])
+	writePublicKeyOption(buf, params.FreezeAuthority)
+	return buf.Bytes()
+}
+
+func NewInitializeAccount(params InitializeAccountParams) (transaction.ITransactionInstruction, error) {
+	return newTokenInstruction(params.ProgramId, []*transaction.AccountMeta{
+		transaction.NewAccountMeta(params.Account, false, true),
+		transaction.NewAccountMeta(params.Mint, false, false),
+		transaction.NewAccountMeta(params.Owner, false, false),
+		transaction.NewAccountMeta(sysvar.RentPubkey, false, false),
+	}, []byte{InstructionInitializeAccount})
+}
+
+/*
+owner放在指令数据中，不需要owner和rent sysvar账户
+*/
+func NewInitializeAccount3(params InitializeAccountParams) (transaction.ITransactionInstruction, error) {
+	data := append([]byte{InstructionInitializeAccount3}, params.Owner[:]...)
+	return newTokenInstruction(params.ProgramId, []*transaction.AccountMeta{
+		transaction.NewAccountMeta(params.Account, false, true),
+		transaction.NewAccountMeta(params.Mint, false, false),
+	}, data)
+}
+
+func NewInitializeMultisig(params InitializeMultisigParams) (transaction.ITransactionInstruction, error) {
+	if len(params.Signers) < MinSigners || len(params.Signers) > MaxSigners {
+		return nil, fmt.Errorf("multisig signers length [%d] is not in [%d,%d]", len(params.Signers), MinSigners, MaxSigners)
+	}
+	if params.M < MinSigners || int(params.M) > len(params.Signers) {
+		return nil, fmt.Errorf("multisig m [%d] is not in [%d,%d]", params.M, MinSigners, len(params.Signers))
+	}
+	keys := []*transaction.AccountMeta{
+		transaction.NewAccountMeta(params.Multisig, false, true),
+		transaction.NewAccountMeta(sysvar.RentPubkey, false, false),
+	}
+	for _, signer := range params.Signers {
+		keys = append(keys, transaction.NewAccountMeta(signer, false, false))
+	}
+	return newTokenInstruction(params.ProgramId, keys, []byte{InstructionInitializeMultisig, params.M})
+}
+
+func NewTransfer(params TransferParams) (transaction.ITransactionInstruction, error) {
+	keys := []*transaction.AccountMeta{
+		transaction.NewAccountMeta(params.Source, false, true),
+		transaction.NewAccountMeta(params.Destination, false, true),
+	}
+	keys = appendOwner(keys, params.Owner, params.MultiSigners)
+	return newTokenInstruction(params.ProgramId, keys, encodeAmount(InstructionTransfer, params.Amount))
+}
+
+/*
+会校验mint和decimals，硬件钱包等场景推荐使用
+*/
+func NewTransferChecked(params TransferCheckedParams) (transaction.ITransactionInstruction, error) {
+	keys := []*transaction.AccountMeta{
+		transaction.NewAccountMeta(params.Source, false, true),
+		transaction.NewAccountMeta(params.Mint, false, false),
+		transaction.NewAccountMeta(params.Destination, false, true),
+	}
+	keys = appendOwner(keys, params.Owner, params.MultiSigners)
+	data := append(encodeAmount(InstructionTransferChecked, params.Amount), params.Decimals)
+	return newTokenInstruction(params.ProgramId, keys, data)
+}
+
+func NewMintTo(params MintToParams) (transaction.ITransactionInstruction, error) {
+	keys := []*transaction.AccountMeta{
+		transaction.NewAccountMeta(params.Mint, false, true),
+		transaction.NewAccountMeta(params.Destination, false, true),
+	}
+	keys = appendOwner(keys, params.Authority, params.MultiSigners)
+	return newTokenInstruction(params.ProgramId, keys, encodeAmount(InstructionMintTo, params.Amount))
+}
+
+func NewBurn(params BurnParams) (transaction.ITransactionInstruction, error) {
+	keys := []*transaction.AccountMeta{
+		transaction.NewAccountMeta(params.Account, false, true),
+		transaction.NewAccountMeta(params.Mint, false, true),
+	}
+	keys = appendOwner(keys, params.Owner, params.MultiSigners)
+	return newTokenInstruction(params.ProgramId, keys, encodeAmount(InstructionBurn, params.Amount))
+}
+
+func NewApprove(params ApproveParams) (transaction.ITransactionInstruction, error) {
+	keys := []*transaction.AccountMeta{
+		transaction.NewAccountMeta(params.Source, false, true),
+		transaction.NewAccountMeta(params.Delegate, false, false),
+	}
+	keys = appendOwner(keys, params.Owner, params.MultiSigners)
+	return newTokenInstruction(params.ProgramId, keys, encodeAmount(InstructionApprove, params.Amount))
+}
+
+func NewRevoke(params RevokeParams) (transaction.ITransactionInstruction, error) {
+	keys := []*transaction.AccountMeta{
+		transaction.NewAccountMeta(params.Source, false, true),
+	}
+	keys = appendOwner(keys, params.Owner, params.MultiSigners)
+	return newTokenInstruction(params.ProgramId, keys, []byte{InstructionRevoke})
+}
+
+/*
+AuthorityCloseAccount之后的类型只有Token-2022支持
+*/
+func NewSetAuthority(params SetAuthorityParams) (transaction.ITransactionInstruction, error) {
+	maxAuthorityType := AuthorityCloseAccount
+	if params.ProgramId == Token2022ProgramId {
+		maxAuthorityType = AuthorityGroupMemberPointer
+	}
+	if params.AuthorityType > maxAuthorityType {
+		return nil, fmt.Errorf("authority type [%d] is not valid", params.AuthorityType)
+	}
+	keys := []*transaction.AccountMeta{
+		transaction.NewAccountMeta(params.Account, false, true),
+	}
+	keys = appendOwner(keys, params.CurrentAuthority, params.MultiSigners)
+	buf := new(bytes.Buffer)
+	buf.WriteByte(InstructionSetAuthority)
+	buf.WriteByte(uint8(params.AuthorityType))
+	writePublicKeyOption(buf, params.NewAuthority)
+	return newTokenInstruction(params.ProgramId, keys, buf.Bytes())
+}
+
+/*
+token余额必须为0，剩余的lamports转到Destination；wrapped SOL账户可以直接关闭
+*/
+func NewCloseAccount(params CloseAccountParams) (transaction.ITransactionInstruction, error) {
+	keys := []*transaction.AccountMeta{
+		transaction.NewAccountMeta(params.Account, false, true),
+		transaction.NewAccountMeta(params.Destination, false, true),
+	}
+	keys = appendOwner(keys, params.Owner, params.MultiSigners)
+	return newTokenInstruction(params.ProgramId, keys, []byte{InstructionCloseAccount})
+}
+
+func NewFreezeAccount(params FreezeAccountParams) (transaction.ITransactionInstruction, error) {
+	keys := []*transaction.AccountMeta{
+		transaction.NewAccountMeta(params.Account, false, true),
+		transaction.NewAccountMeta(params.Mint, false, false),
+	}
+	keys = appendOwner(keys, params.Authority, params.MultiSigners)
+	return newTokenInstruction(params.ProgramId, keys, []byte{InstructionFreezeAccount})
+}
+
+func NewThawAccount(params ThawAccountParams) (transaction.ITransactionInstruction, error) {
+	keys := []*transaction.AccountMeta{
+		transaction.NewAccountMeta(params.Account, false, true),
+		transaction.NewAccountMeta(params.Mint, false, false),
+	}
+	keys = appendOwner(keys, params.Authority, params.MultiSigners)
+	return newTokenInstruction(params.ProgramId, keys, []byte{InstructionThawAccount})
+}
+
+/*
+wrapped SOL账户收到lamports后，同步token余额
+*/
+func NewSyncNative(params SyncNativeParams) (transaction.ITransactionInstruction, error) {
+	return newTokenInstruction(params.ProgramId, []*transaction.AccountMeta{
+		transaction.NewAccountMeta(params.Account, false, true),
+	}, []byte{InstructionSyncNative})
+}
+
+/*
+单签时owner为签名账户；多签时owner为只读的多签账户，后面跟着各个签名账户
+*/
+func appendOwner(keys []*transaction.AccountMeta, owner account.PublicKey, multiSigners []account.PublicKey) []*transaction.AccountMeta {
+	if len(multiSigners) == 0 {
+		return append(keys, transaction.NewAccountMeta(owner, true, false))
+	}
+	keys = append(keys, transaction.NewAccountMeta(owner, false, false))
+	for _, signer := range multiSigners {
+		keys = append(keys, transaction.NewAccountMeta(signer, true, false))
+	}
+	return keys
+}
+
+func encodeAmount(index uint8, amount uint64) []byte {
+	data := make([]byte, 9)
+	data[0] = index
+	binary.LittleEndian.PutUint64(data[1:], amount)
+	return data
+}
+
+/*
+COption<Pubkey>：u8 tag | 32字节公钥，与web3.js的layout一致，None时公钥为全0
+*/
+func writePublicKeyOption(buf *bytes.Buffer, pubkey *account.PublicKey) {
+	if pubkey == nil {
+		buf.WriteByte(0)
+		buf.Write(make([]byte, account.PublicKeySize))
+		return
+	}
+	buf.WriteByte(1)
+	buf.Write(pubkey[:])
+}
+
+func newTokenInstruction(programId account.PublicKey, keys []*transaction.AccountMeta, data []byte) (transaction.ITransactionInstruction, error) {
+	if programId.IsZero() {
+		programId = ProgramId
+	}
+	return transaction.NewTransactionInstruction(programId, keys, data)
+}
@@ -0,0 +1,301 @@
+package token
+
+/*
+func：Token-2022扩展指令
+author： flynn
+date: 2020-08-03
+fork: https://github.com/solana-labs/solana-program-library/token/js/src/extensions
+*/
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"github.com/JFJun/solana-go/account"
+	"github.com/JFJun/solana-go/transaction"
+)
+
+/*
+spl token metadata interface的initialize指令标识：sha256("spl_token_metadata_interface:initialize_account")[:8]
+*/
+var tokenMetadataInitializeDiscriminator = func() []byte {
+	hash := sha256.Sum256([]byte("spl_token_metadata_interface:initialize_account"))
+	return hash[:8]
+}()
+
+/*
+Fee必须与链上按当前epoch计算出的手续费一致，可以用TransferFeeConfig.CalculateEpochFee计算
+*/
+type TransferCheckedWithFeeParams struct {
+	Source       account.PublicKey
+	Mint         account.PublicKey
+	Destination  account.PublicKey
+	Owner        account.PublicKey
+	Amount       uint64
+	Decimals     uint8
+	Fee          uint64
+	MultiSigners []account.PublicKey
+}
+
+/*
+把token账户中预扣的手续费归集到mint，不需要签名
+*/
+type HarvestWithheldTokensToMintParams struct {
+	Mint    account.PublicKey
+	Sources []account.PublicKey
+}
+
+type WithdrawWithheldTokensFromMintParams struct {
+	Mint         account.PublicKey
+	Destination  account.PublicKey
+	Authority    account.PublicKey // withdraw withheld authority
+	MultiSigners []account.PublicKey
+}
+
+type WithdrawWithheldTokensFromAccountsParams struct {
+	Mint         account.PublicKey
+	Destination  account.PublicKey
+	Authority    account.PublicKey
+	Sources      []account.PublicKey
+	MultiSigners []account.PublicKey
+}
+
+type InitializeTransferFeeConfigParams struct {
+	Mint                       account.PublicKey
+	TransferFeeConfigAuthority *account.PublicKey
+	WithdrawWithheldAuthority  *account.PublicKey
+	TransferFeeBasisPoints     uint16
+	MaximumFee                 uint64
+}
+
+type InitializeMintCloseAuthorityParams struct {
+	Mint           account.PublicKey
+	CloseAuthority *account.PublicKey
+}
+
+type InitializeDefaultAccountStateParams struct {
+	Mint  account.PublicKey
+	State AccountState
+}
+
+type InitializeInterestBearingMintParams struct {
+	Mint          account.PublicKey
+	RateAuthority account.PublicKey
+	Rate          int16 // 基点
+}
+
+type InitializeTransferHookParams struct {
+	Mint          account.PublicKey
+	Authority     account.PublicKey
+	HookProgramId account.PublicKey
+}
+
+type InitializeMetadataPointerParams struct {
+	Mint            account.PublicKey
+	Authority       account.PublicKey
+	MetadataAddress account.PublicKey // 元数据保存在mint中时为mint自身
+}
+
+type InitializePermanentDelegateParams struct {
+	Mint     account.PublicKey
+	Delegate account.PublicKey
+}
+
+type InitializeTokenMetadataParams struct {
+	Metadata        account.PublicKey
+	UpdateAuthority account.PublicKey
+	Mint            account.PublicKey
+	MintAuthority   account.PublicKey
+	Name            string
+	Symbol          string
+	Uri             string
+}
+
+type MemoTransferParams struct {
+	Account      account.PublicKey
+	Owner        account.PublicKey
+	MultiSigners []account.PublicKey
+}
+
+func NewTransferCheckedWithFee(params TransferCheckedWithFeeParams) (transaction.ITransactionInstruction, error) {
+	keys := []*transaction.AccountMeta{
+		transaction.NewAccountMeta(params.Source, false, true),
+		transaction.NewAccountMeta(params.Mint, false, false),
+		transaction.NewAccountMeta(params.Destination, false, true),
+	}
+	keys = appendOwner(keys, params.Owner, params.MultiSigners)
+	data := make([]byte, 19)
+	data[0] = InstructionTransferFeeExtension
+	data[1] = TransferFeeTransferCheckedWithFee
+	binary.LittleEndian.PutUint64(data[2:10], params.Amount)
+	data[10] = params.Decimals
+	binary.LittleEndian.PutUint64(data[11:19], params.Fee)
+	return newToken2022Instruction(keys, data)
+}
+
+func NewHarvestWithheldTokensToMint(params HarvestWithheldTokensToMintParams) (transaction.ITransactionInstruction, error) {
+	keys := []*transaction.AccountMeta{
+		transaction.NewAccountMeta(params.Mint, false, true),
+	}
+	for _, source := range params.Sources {
+		keys = append(keys, transaction.NewAccountMeta(source, false, true))
+	}
+	return newToken2022Instruction(keys, []byte{InstructionTransferFeeExtension, TransferFeeHarvestWithheldTokensToMint})
+}
+
+func NewWithdrawWithheldTokensFromMint(params WithdrawWithheldTokensFromMintParams) (transaction.ITransactionInstruction, error) {
+	keys := []*transaction.AccountMeta{
+		transaction.NewAccountMeta(params.Mint, false, true),
+		transaction.NewAccountMeta(params.Destination, false, true),
+	}
+	keys = appendOwner(keys, params.Authority, params.MultiSigners)
+	return newToken2022Instruction(keys, []byte{InstructionTransferFeeExtension, TransferFeeWithdrawWithheldTokensFromMint})
+}
+
+func NewWithdrawWithheldTokensFromAccounts(params WithdrawWithheldTokensFromAccountsParams) (transaction.ITransactionInstruction, error) {
+	if len(params.Sources) == 0 || len(params.Sources) > 255 {
+		return nil, fmt.Errorf("withdraw withheld sources length [%d] is not valid", len(params.Sources))
+	}
+	keys := []*transaction.AccountMeta{
+		transaction.NewAccountMeta(params.Mint, false, false),
+		transaction.NewAccountMeta(params.Destination, false, true),
+	}
+	keys = appendOwner(keys, params.Authority, params.MultiSigners)
+	for _, source := range params.Sources {
+		keys = append(keys, transaction.NewAccountMeta(source, false, true))
+	}
+	return newToken2022Instruction(keys, []byte{
+		InstructionTransferFeeExtension, TransferFeeWithdrawWithheldTokensFromAccounts, uint8(len(params.Sources)),
+	})
+}
+
+/*
+以下为mint扩展的初始化指令，需要在InitializeMint之前执行，且mint的空间需要按GetMintLen分配
+*/
+func NewInitializeTransferFeeConfig(params InitializeTransferFeeConfigParams) (transaction.ITransactionInstruction, error) {
+	if params.TransferFeeBasisPoints > 10000 {
+		return nil, fmt.Errorf("transfer fee basis points [%d] is greater than 10000", params.TransferFeeBasisPoints)
+	}
+	buf := new(bytes.Buffer)
+	buf.WriteByte(InstructionTransferFeeExtension)
+	buf.WriteByte(TransferFeeInitializeConfig)
+	writePackedPublicKeyOption(buf, params.TransferFeeConfigAuthority)
+	writePackedPublicKeyOption(buf, params.WithdrawWithheldAuthority)
+	binary.Write(buf, binary.LittleEndian, params.TransferFeeBasisPoints)
+	binary.Write(buf, binary.LittleEndian, params.MaximumFee)
+	return newMintExtensionInstruction(params.Mint, buf.Bytes())
+}
+
+func NewInitializeMintCloseAuthority(params InitializeMintCloseAuthorityParams) (transaction.ITransactionInstruction, error) {
+	buf := new(bytes.Buffer)
+	buf.WriteByte(InstructionInitializeMintCloseAuthority)
+	writePackedPublicKeyOption(buf, params.CloseAuthority)
+	return newMintExtensionInstruction(params.Mint, buf.Bytes())
+}
+
+func NewInitializeDefaultAccountState(params InitializeDefaultAccountStateParams) (transaction.ITransactionInstruction, error) {
+	if params.State > AccountStateFrozen {
+		return nil, fmt.Errorf("default account state [%d] is not valid", params.State)
+	}
+	return newMintExtensionInstruction(params.Mint, []byte{
+		InstructionDefaultAccountStateExtension, ExtensionInitialize, uint8(params.State),
+	})
+}
+
+func NewInitializeInterestBearingMint(params InitializeInterestBearingMintParams) (transaction.ITransactionInstruction, error) {
+	data := []byte{InstructionInterestBearingMintExtension, ExtensionInitialize}
+	data = append(data, params.RateAuthority[:]...)
+	data = append(data, byte(params.Rate), byte(uint16(params.Rate)>>8))
+	return newMintExtensionInstruction(params.Mint, data)
+}
+
+func NewInitializeTransferHook(params InitializeTransferHookParams) (transaction.ITransactionInstruction, error) {
+	data := []byte{InstructionTransferHookExtension, ExtensionInitialize}
+	data = append(data, params.Authority[:]...)
+	data = append(data, params.HookProgramId[:]...)
+	return newMintExtensionInstruction(params.Mint, data)
+}
+
+func NewInitializeMetadataPointer(params InitializeMetadataPointerParams) (transaction.ITransactionInstruction, error) {
+	data := []byte{InstructionMetadataPointerExtension, ExtensionInitialize}
+	data = append(data, params.Authority[:]...)
+	data = append(data, params.MetadataAddress[:]...)
+	return newMintExtensionInstruction(params.Mint, data)
+}
+
+func NewInitializePermanentDelegate(params InitializePermanentDelegateParams) (transaction.ITransactionInstruction, error) {
+	data := append([]byte{InstructionInitializePermanentDelegate}, params.Delegate[:]...)
+	return newMintExtensionInstruction(params.Mint, data)
+}
+
+func NewInitializeNonTransferableMint(mint account.PublicKey) (transaction.ITransactionInstruction, error) {
+	return newMintExtensionInstruction(mint, []byte{InstructionInitializeNonTransferableMint})
+}
+
+/*
+token账户的扩展初始化，需要在InitializeAccount之前执行
+*/
+func NewInitializeImmutableOwner(tokenAccount account.PublicKey) (transaction.ITransactionInstruction, error) {
+	return newToken2022Instruction([]*transaction.AccountMeta{
+		transaction.NewAccountMeta(tokenAccount, false, true),
+	}, []byte{InstructionInitializeImmutableOwner})
+}
+
+/*
+开启后转入该账户的交易必须带memo
+*/
+func NewEnableRequiredMemoTransfers(params MemoTransferParams) (transaction.ITransactionInstruction, error) {
+	return newMemoTransferInstruction(params, MemoTransferEnable)
+}
+
+func NewDisableRequiredMemoTransfers(params MemoTransferParams) (transaction.ITransactionInstruction, error) {
+	return newMemoTransferInstruction(params, MemoTransferDisable)
+}
+
+func newMemoTransferInstruction(params MemoTransferParams, sub uint8) (transaction.ITransactionInstruction, error) {
+	keys := []*transaction.AccountMeta{
+		transaction.NewAccountMeta(params.Account, false, true),
+	}
+	keys = appendOwner(keys, params.Owner, params.MultiSigners)
+	return newToken2022Instruction(keys, []byte{InstructionMemoTransferExtension, sub})
+}
+
+/*
+在MetadataPointer指向的账户（一般为mint自身）中写入元数据，mint账户需要预留足够的lamports
+*/
+func NewInitializeTokenMetadata(params InitializeTokenMetadataParams) (transaction.ITransactionInstruction, error) {
+	buf := new(bytes.Buffer)
+	buf.Write(tokenMetadataInitializeDiscriminator)
+	for _, s := range []string{params.Name, params.Symbol, params.Uri} {
+		binary.Write(buf, binary.LittleEndian, uint32(len(s)))
+		buf.WriteString(s)
+	}
+	return newToken2022Instruction([]*transaction.AccountMeta{
+		transaction.NewAccountMeta(params.Metadata, false, true),
+		transaction.NewAccountMeta(params.UpdateAuthority, false, false),
+		transaction.NewAccountMeta(params.Mint, false, false),
+		transaction.NewAccountMeta(params.MintAuthority, true, false),
+	}, buf.Bytes())
+}
+
+/*
+与程序的unpack一致：None只有一个字节的tag，后面的参数紧跟其后
+*/
+func writePackedPublicKeyOption(buf *bytes.Buffer, pubkey *account.PublicKey) {
+	if pubkey == nil {
+		buf.WriteByte(0)
+		return
+	}
+	buf.WriteByte(1)
+	buf.Write(pubkey[:])
+}
+
+func newMintExtensionInstruction(mint account.PublicKey, data []byte) (transaction.ITransactionInstruction, error) {
+	return newToken2022Instruction([]*transaction.AccountMeta{
+		transaction.NewAccountMeta(mint, false, true),
+	}, data)
+}
+
+func newToken2022Instruction(keys []*transaction.AccountMeta, data []byte) (transaction.ITransactionInstruction, error) {
+	return transaction.NewTransactionInstruction(Token2022ProgramId, keys, data)
+}
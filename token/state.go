@@ -0,0 +1,249 @@
+package token
+
+/*
+func：
+author： flynn
+date: 2020-08-03
+fork: https://github.com/solana-labs/solana-program-library/token/js/src/state
+*/
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/JFJun/solana-go/account"
+	"github.com/JFJun/solana-go/rpc"
+)
+
+/*
+wrapped SOL的mint地址
+*/
+var NativeMint = account.MustPublicKeyFromBase58("So11111111111111111111111111111111111111112")
+
+/*
+账户数据长度
+*/
+const (
+	MintSize     = 82
+	AccountSize  = 165
+	MultisigSize = 355
+)
+
+type AccountState uint8
+
+const (
+	AccountStateUninitialized AccountState = iota
+	AccountStateInitialized
+	AccountStateFrozen
+)
+
+type Mint struct {
+	MintAuthority   *account.PublicKey // 为nil表示不能再增发
+	Supply          uint64
+	Decimals        uint8
+	IsInitialized   bool
+	FreezeAuthority *account.PublicKey
+	Extensions      Extensions // Token-2022扩展，spl token为空
+}
+
+type Account struct {
+	Mint            account.PublicKey
+	Owner           account.PublicKey
+	Amount          uint64
+	Delegate        *account.PublicKey
+	State           AccountState
+	IsNative        *uint64 // wrapped SOL账户，值为免租金额，这部分lamports不计入Amount
+	DelegatedAmount uint64
+	CloseAuthority  *account.PublicKey
+	Extensions      Extensions // Token-2022扩展，spl token为空
+}
+
+func (acc *Account) IsFrozen() bool {
+	return acc.State == AccountStateFrozen
+}
+
+type Multisig struct {
+	M             uint8 // 需要的签名数量
+	N             uint8 // 有效的签名者数量
+	IsInitialized bool
+	Signers       []account.PublicKey // 只包含前N个
+}
+
+/*
+mint数据：COption<Pubkey> mint authority | u64 supply | u8 decimals | bool initialized | COption<Pubkey> freeze authority
+账户中的COption使用u32作为tag
+Token-2022的mint在基础数据之后补0到AccountSize，再跟accountType和扩展
+*/
+func DeserializeMint(data []byte) (*Mint, error) {
+	var (
+		exts Extensions
+		err  error
+	)
+	if len(data) != MintSize {
+		if exts, err = parseExtensions(data, AccountTypeMint); err != nil {
+			return nil, fmt.Errorf("mint data length is %d, not valid: %v", len(data), err)
+		}
+		for _, b := range data[MintSize:AccountSize] {
+			if b != 0 {
+				return nil, errors.New("mint padding is not zero")
+			}
+		}
+	}
+	mintAuthority, err := readPublicKeyOption(data[0:36])
+	if err != nil {
+		return nil, err
+	}
+	freezeAuthority, err := readPublicKeyOption(data[46:82])
+	if err != nil {
+		return nil, err
+	}
+	isInitialized, err := readBool(data[45])
+	if err != nil {
+		return nil, err
+	}
+	return &Mint{
+		MintAuthority:   mintAuthority,
+		Supply:          binary.LittleEndian.Uint64(data[36:44]),
+		Decimals:        data[44],
+		IsInitialized:   isInitialized,
+		FreezeAuthority: freezeAuthority,
+		Extensions:      exts,
+	}, nil
+}
+
+/*
+token账户数据：mint | owner | u64 amount | COption<Pubkey> delegate | u8 state |
+COption<u64> is native | u64 delegated amount | COption<Pubkey> close authority
+*/
+func DeserializeAccount(data []byte) (*Account, error) {
+	var (
+		exts Extensions
+		err  error
+	)
+	if len(data) != AccountSize {
+		if exts, err = parseExtensions(data, AccountTypeAccount); err != nil {
+			return nil, fmt.Errorf("token account data length is %d, not valid: %v", len(data), err)
+		}
+	}
+	acc := &Account{
+		Extensions:      exts,
+		Amount:          binary.LittleEndian.Uint64(data[64:72]),
+		State:           AccountState(data[108]),
+		DelegatedAmount: binary.LittleEndian.Uint64(data[121:129]),
+	}
+	if acc.State > AccountStateFrozen {
+		return nil, fmt.Errorf("token account state [%d] is not valid", acc.State)
+	}
+	copy(acc.Mint[:], data[0:32])
+	copy(acc.Owner[:], data[32:64])
+	if acc.Delegate, err = readPublicKeyOption(data[72:108]); err != nil {
+		return nil, err
+	}
+	switch binary.LittleEndian.Uint32(data[109:113]) {
+	case 0:
+	case 1:
+		reserve := binary.LittleEndian.Uint64(data[113:121])
+		acc.IsNative = &reserve
+	default:
+		return nil, fmt.Errorf("coption tag [%d] is not valid", binary.LittleEndian.Uint32(data[109:113]))
+	}
+	if acc.CloseAuthority, err = readPublicKeyOption(data[129:165]); err != nil {
+		return nil, err
+	}
+	return acc, nil
+}
+
+/*
+多签账户数据：u8 m | u8 n | bool initialized | [11]Pubkey signers
+*/
+func DeserializeMultisig(data []byte) (*Multisig, error) {
+	if len(data) != MultisigSize {
+		return nil, fmt.Errorf("multisig data length is %d, not equal %d", len(data), MultisigSize)
+	}
+	isInitialized, err := readBool(data[2])
+	if err != nil {
+		return nil, err
+	}
+	multisig := &Multisig{
+		M:             data[0],
+		N:             data[1],
+		IsInitialized: isInitialized,
+	}
+	if multisig.N > MaxSigners {
+		return nil, fmt.Errorf("multisig signer number [%d] is greater than %d", multisig.N, MaxSigners)
+	}
+	for i := 0; i < int(multisig.N); i++ {
+		var signer account.PublicKey
+		offset := 3 + i*account.PublicKeySize
+		copy(signer[:], data[offset:offset+account.PublicKeySize])
+		multisig.Signers = append(multisig.Signers, signer)
+	}
+	return multisig, nil
+}
+
+/*
+通过rpc读取mint，programId为空时使用spl token program
+*/
+func GetMint(client *rpc.RpcClient, mint, programId account.PublicKey) (*Mint, error) {
+	data, err := getProgramAccountData(client, mint, programId)
+	if err != nil {
+		return nil, err
+	}
+	return DeserializeMint(data)
+}
+
+func GetAccount(client *rpc.RpcClient, tokenAccount, programId account.PublicKey) (*Account, error) {
+	data, err := getProgramAccountData(client, tokenAccount, programId)
+	if err != nil {
+		return nil, err
+	}
+	return DeserializeAccount(data)
+}
+
+func GetMultisig(client *rpc.RpcClient, multisig, programId account.PublicKey) (*Multisig, error) {
+	data, err := getProgramAccountData(client, multisig, programId)
+	if err != nil {
+		return nil, err
+	}
+	return DeserializeMultisig(data)
+}
+
+func getProgramAccountData(client *rpc.RpcClient, key, programId account.PublicKey) ([]byte, error) {
+	if programId.IsZero() {
+		programId = ProgramId
+	}
+	info, err := client.GetAccountInfo(key)
+	if err != nil {
+		return nil, err
+	}
+	if info == nil {
+		return nil, fmt.Errorf("account [%s] is not found", key.String())
+	}
+	if info.Owner != programId {
+		return nil, fmt.Errorf("account [%s] is not owned by %s", key.String(), programId.String())
+	}
+	return info.Data, nil
+}
+
+func readPublicKeyOption(data []byte) (*account.PublicKey, error) {
+	switch tag := binary.LittleEndian.Uint32(data[0:4]); tag {
+	case 0:
+		return nil, nil
+	case 1:
+		var pubkey account.PublicKey
+		copy(pubkey[:], data[4:4+account.PublicKeySize])
+		return &pubkey, nil
+	default:
+		return nil, fmt.Errorf("coption tag [%d] is not valid", tag)
+	}
+}
+
+func readBool(b byte) (bool, error) {
+	switch b {
+	case 0:
+		return false, nil
+	case 1:
+		return true, nil
+	default:
+		return false, fmt.Errorf("bool value [%d] is not valid", b)
+	}
+}
@@ -0,0 +1,58 @@
+package token
+
+/*
+func：
+author： flynn
+date: 2020-08-03
+fork: https://github.com/solana-labs/solana-program-library/token/js/src/instructions
+*/
+import "github.com/JFJun/solana-go/account"
+
+/*
+spl token program的id
+*/
+var ProgramId = account.MustPublicKeyFromBase58("TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA")
+
+/*
+spl token指令序号，与spl_token::instruction::TokenInstruction的顺序一致
+*/
+const (
+	InstructionInitializeMint uint8 = iota
+	InstructionInitializeAccount
+	InstructionInitializeMultisig
+	InstructionTransfer
+	InstructionApprove
+	InstructionRevoke
+	InstructionSetAuthority
+	InstructionMintTo
+	InstructionBurn
+	InstructionCloseAccount
+	InstructionFreezeAccount
+	InstructionThawAccount
+	InstructionTransferChecked
+	InstructionApproveChecked
+	InstructionMintToChecked
+	InstructionBurnChecked
+	InstructionInitializeAccount2
+	InstructionSyncNative
+	InstructionInitializeAccount3
+	InstructionInitializeMultisig2
+	InstructionInitializeMint2
+)
+
+type AuthorityType uint8
+
+const (
+	AuthorityMintTokens AuthorityType = iota
+	AuthorityFreezeAccount
+	AuthorityAccountOwner
+	AuthorityCloseAccount
+)
+
+/*
+多签账户的签名者数量限制
+*/
+const (
+	MinSigners = 1
+	MaxSigners = 11
+)
@@ -0,0 +1,143 @@
+package token
+
+/*
+func：
+author： flynn
+date: 2020-08-03
+fork: https://github.com/solana-labs/solana-program-library/token/js/src/extensions
+*/
+import "github.com/JFJun/solana-go/account"
+
+/*
+Token-2022 program的id，基础指令与spl token完全兼容，通过参数中的ProgramId指定
+*/
+var Token2022ProgramId = account.MustPublicKeyFromBase58("TokenzQdBNbLqP5VEhdkAS6EPFLC1PHnBqCXEpPxuEb")
+
+/*
+Token-2022新增的指令序号，接在InstructionInitializeMint2之后
+*/
+const (
+	InstructionGetAccountDataSize uint8 = iota + InstructionInitializeMint2 + 1
+	InstructionInitializeImmutableOwner
+	InstructionAmountToUiAmount
+	InstructionUiAmountToAmount
+	InstructionInitializeMintCloseAuthority
+	InstructionTransferFeeExtension
+	InstructionConfidentialTransferExtension
+	InstructionDefaultAccountStateExtension
+	InstructionReallocate
+	InstructionMemoTransferExtension
+	InstructionCreateNativeMint
+	InstructionInitializeNonTransferableMint
+	InstructionInterestBearingMintExtension
+	InstructionCpiGuardExtension
+	InstructionInitializePermanentDelegate
+	InstructionTransferHookExtension
+	InstructionConfidentialTransferFeeExtension
+	InstructionWithdrawExcessLamports
+	InstructionMetadataPointerExtension
+	InstructionGroupPointerExtension
+	InstructionGroupMemberPointerExtension
+)
+
+/*
+TransferFeeExtension的子指令
+*/
+const (
+	TransferFeeInitializeConfig uint8 = iota
+	TransferFeeTransferCheckedWithFee
+	TransferFeeWithdrawWithheldTokensFromMint
+	TransferFeeWithdrawWithheldTokensFromAccounts
+	TransferFeeHarvestWithheldTokensToMint
+	TransferFeeSetTransferFee
+)
+
+/*
+DefaultAccountState、MemoTransfer、InterestBearingMint、TransferHook、MetadataPointer等扩展的子指令
+*/
+const (
+	ExtensionInitialize uint8 = iota
+	ExtensionUpdate
+)
+
+const (
+	MemoTransferEnable uint8 = iota
+	MemoTransferDisable
+)
+
+/*
+Token-2022新增的AuthorityType，接在AuthorityCloseAccount之后，只能用于Token-2022
+*/
+const (
+	AuthorityTransferFeeConfig AuthorityType = iota + AuthorityCloseAccount + 1
+	AuthorityWithheldWithdraw
+	AuthorityCloseMint
+	AuthorityInterestRate
+	AuthorityPermanentDelegate
+	AuthorityConfidentialTransferMint
+	AuthorityTransferHookProgramId
+	AuthorityConfidentialTransferFeeConfig
+	AuthorityMetadataPointer
+	AuthorityGroupPointer
+	AuthorityGroupMemberPointer
+)
+
+/*
+Token-2022账户类型，位于基础数据（按AccountSize补齐）之后
+*/
+const (
+	AccountTypeUninitialized uint8 = iota
+	AccountTypeMint
+	AccountTypeAccount
+)
+
+type ExtensionType uint16
+
+const (
+	ExtensionUninitialized ExtensionType = iota
+	ExtensionTransferFeeConfig
+	ExtensionTransferFeeAmount
+	ExtensionMintCloseAuthority
+	ExtensionConfidentialTransferMint
+	ExtensionConfidentialTransferAccount
+	ExtensionDefaultAccountState
+	ExtensionImmutableOwner
+	ExtensionMemoTransfer
+	ExtensionNonTransferable
+	ExtensionInterestBearingConfig
+	ExtensionCpiGuard
+	ExtensionPermanentDelegate
+	ExtensionNonTransferableAccount
+	ExtensionTransferHook
+	ExtensionTransferHookAccount
+	ExtensionConfidentialTransferFeeConfig
+	ExtensionConfidentialTransferFeeAmount
+	ExtensionMetadataPointer
+	ExtensionTokenMetadata
+	ExtensionGroupPointer
+	ExtensionTokenGroup
+	ExtensionGroupMemberPointer
+	ExtensionTokenGroupMember
+)
+
+/*
+定长扩展的数据长度，TokenMetadata等变长扩展不在其中
+*/
+var extensionLength = map[ExtensionType]int{
+	ExtensionTransferFeeConfig:      108,
+	ExtensionTransferFeeAmount:      8,
+	ExtensionMintCloseAuthority:     32,
+	ExtensionDefaultAccountState:    1,
+	ExtensionImmutableOwner:         0,
+	ExtensionMemoTransfer:           1,
+	ExtensionNonTransferable:        0,
+	ExtensionInterestBearingConfig:  52,
+	ExtensionCpiGuard:               1,
+	ExtensionPermanentDelegate:      32,
+	ExtensionNonTransferableAccount: 0,
+	ExtensionTransferHook:           64,
+	ExtensionTransferHookAccount:    1,
+	ExtensionMetadataPointer:        64,
+	ExtensionGroupPointer:           64,
+	ExtensionGroupMemberPointer:     64,
+}
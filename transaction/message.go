@@ -7,6 +7,9 @@ date: 2020-08-03
 fork: https://github.com/solana-labs/solana-web3.js/src/message.js
 */
 import (
+	"errors"
+	"fmt"
+	"github.com/JFJun/solana-go/account"
 	"github.com/btcsuite/btcutil/base58"
 )
 
@@ -20,7 +23,7 @@ const PACK_DATA_SIZE = 1280 - 40 - 8
 */
 type Message struct {
 	Header          *MessageHeader
-	AccountKeys     []string
+	AccountKeys     []account.PublicKey
 	RecentBlockHash string
 	Instructions    []*CompiledInstruction
 }
@@ -28,7 +31,7 @@ type Message struct {
 type CompiledInstruction struct {
 	ProgramIdIndex int
 	Accounts       []int
-	Data           string
+	Data           []byte
 }
 type MessageHeader struct {
 	NumRequiredSignatures       int
@@ -36,59 +39,252 @@ type MessageHeader struct {
 	NumReadonlyUnsignedAccounts int
 }
 
+func (message *Message) IsAccountSigner(index int) bool {
+	return index < message.Header.NumRequiredSignatures
+}
+
+func (message *Message) IsAccountWritable(index int) bool {
+	numSigned := message.Header.NumRequiredSignatures
+	if index < numSigned {
+		return index < numSigned-message.Header.NumReadonlySignedAccounts
+	}
+	return index < len(message.AccountKeys)-message.Header.NumReadonlyUnsignedAccounts
+}
+
 func (message *Message) Serialize() []byte {
-	numKey := len(message.AccountKeys)
-	keyCount := encodeLength(numKey)
-	var instructionBuffer []byte
+	return message.appendTo(make([]byte, 0, message.serializedSize()))
+}
+
+/*
+预先计算序列化后的长度，保证只分配一次内存
+*/
+func (message *Message) serializedSize() int {
+	size := 3 + shortvecSize(len(message.AccountKeys)) + len(message.AccountKeys)*account.PublicKeySize + 32
+	size += shortvecSize(len(message.Instructions))
 	for _, m := range message.Instructions {
-		accounts, programIdIndex := m.Accounts, m.ProgramIdIndex
-		data := base58.Decode(m.Data)
-		keyIndicesCount := encodeLength(len(accounts))
-
-		dataCount := encodeLength(len(data))
-		var accs []byte
-		for _, a := range accounts {
-			accs = append(accs, byte(a))
-		}
-		instructionBuffer = append(instructionBuffer, byte(programIdIndex))
-		instructionBuffer = append(instructionBuffer, keyIndicesCount...)
-		instructionBuffer = append(instructionBuffer, accs...)
-		instructionBuffer = append(instructionBuffer, dataCount...)
-		instructionBuffer = append(instructionBuffer, data...)
+		size += 1 + shortvecSize(len(m.Accounts)) + len(m.Accounts) + shortvecSize(len(m.Data)) + len(m.Data)
 	}
-	instructionCount := encodeLength(len(message.Instructions))
-
-	instructionBuffer = append(instructionCount, instructionBuffer...)
+	return size
+}
 
-	var signData []byte
-	signData = append(signData, byte(message.Header.NumRequiredSignatures))
-	signData = append(signData, byte(message.Header.NumReadonlySignedAccounts))
-	signData = append(signData, byte(message.Header.NumReadonlyUnsignedAccounts))
-	signData = append(signData, keyCount...)
+func (message *Message) appendTo(signData []byte) []byte {
+	signData = append(signData,
+		byte(message.Header.NumRequiredSignatures),
+		byte(message.Header.NumReadonlySignedAccounts),
+		byte(message.Header.NumReadonlyUnsignedAccounts),
+	)
+	signData = appendLength(signData, len(message.AccountKeys))
 	for _, key := range message.AccountKeys {
-		signData = append(signData, base58.Decode(key)...)
+		signData = append(signData, key[:]...)
+	}
+	var recentBlockHash [32]byte
+	copy(recentBlockHash[:], base58.Decode(message.RecentBlockHash))
+	signData = append(signData, recentBlockHash[:]...)
+	signData = appendLength(signData, len(message.Instructions))
+	for _, m := range message.Instructions {
+		signData = append(signData, byte(m.ProgramIdIndex))
+		signData = appendLength(signData, len(m.Accounts))
+		for _, a := range m.Accounts {
+			signData = append(signData, byte(a))
+		}
+		signData = appendLength(signData, len(m.Data))
+		signData = append(signData, m.Data...)
 	}
-	signData = append(signData, base58.Decode(message.RecentBlockHash)...)
-	signData = append(signData, instructionBuffer...)
 	return signData
 }
 
+func appendLength(data []byte, num int) []byte {
+	for {
+		elem := num & 0x7f
+		num >>= 7
+		if num == 0 {
+			return append(data, byte(elem))
+		}
+		data = append(data, byte(elem|0x80))
+	}
+}
+
+func shortvecSize(num int) int {
+	size := 1
+	for num >= 0x80 {
+		num >>= 7
+		size++
+	}
+	return size
+}
+
 func encodeLength(num int) []byte {
+	return appendLength(nil, num)
+}
 
+/*
+encodeLength的逆过程，返回长度以及占用的字节数；shortvec最多3个字节
+与runtime一致，拒绝非最短编码（例如0x80 0x00）以及超过u16的值
+*/
+func decodeLength(data []byte) (int, int, error) {
 	var (
-		data []byte
-		n    = num
+		length int
+		size   int
 	)
-	for true {
-		elem := n & 0x7f
-		n >>= 7
-		if n == 0 {
-			data = append(data, byte(elem))
+	for {
+		if size >= len(data) {
+			return 0, 0, errors.New("decode length error, unexpected end of data")
+		}
+		if size >= 3 {
+			return 0, 0, errors.New("decode length error, shortvec is too long")
+		}
+		elem := int(data[size])
+		if elem == 0 && size > 0 {
+			return 0, 0, errors.New("decode length error, shortvec is not canonical")
+		}
+		length |= (elem & 0x7f) << uint(size*7)
+		size++
+		if elem&0x80 == 0 {
 			break
-		} else {
-			elem = elem | 0x80
-			data = append(data, byte(elem))
 		}
 	}
-	return data
+	if length > 0xffff {
+		return 0, 0, fmt.Errorf("decode length error, length %d overflows u16", length)
+	}
+	return length, size, nil
+}
+
+/*
+解析序列化后的message，Message.Serialize的逆过程
+*/
+func DeserializeMessage(data []byte) (*Message, error) {
+	if len(data) > 0 && data[0]&0x80 != 0 {
+		return nil, fmt.Errorf("message is versioned, version=%d", data[0]&0x7f)
+	}
+	message, offset, err := decodeMessage(data)
+	if err != nil {
+		return nil, err
+	}
+	if offset != len(data) {
+		return nil, fmt.Errorf("message data has %d unexpected trailing bytes", len(data)-offset)
+	}
+	if err := message.validate(); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+/*
+解析header、accountKeys、recentBlockHash和指令，返回已读取的字节数；legacy与v0 message共用
+*/
+func decodeMessage(data []byte) (*Message, int, error) {
+	if len(data) < 3 {
+		return nil, 0, errors.New("message data is too short")
+	}
+	header := &MessageHeader{
+		NumRequiredSignatures:       int(data[0]),
+		NumReadonlySignedAccounts:   int(data[1]),
+		NumReadonlyUnsignedAccounts: int(data[2]),
+	}
+	offset := 3
+	numKeys, size, err := decodeLength(data[offset:])
+	if err != nil {
+		return nil, 0, fmt.Errorf("decode account keys length error,err=%v", err)
+	}
+	offset += size
+	if len(data) < offset+numKeys*account.PublicKeySize+32 {
+		return nil, 0, errors.New("message data is too short for account keys")
+	}
+	accountKeys := make([]account.PublicKey, numKeys)
+	for i := 0; i < numKeys; i++ {
+		copy(accountKeys[i][:], data[offset:offset+account.PublicKeySize])
+		offset += account.PublicKeySize
+	}
+	recentBlockHash := base58.Encode(data[offset : offset+32])
+	offset += 32
+
+	numInstructions, size, err := decodeLength(data[offset:])
+	if err != nil {
+		return nil, 0, fmt.Errorf("decode instructions length error,err=%v", err)
+	}
+	offset += size
+	var instructions []*CompiledInstruction
+	for i := 0; i < numInstructions; i++ {
+		if offset >= len(data) {
+			return nil, 0, fmt.Errorf("message data is too short for instruction %d", i)
+		}
+		programIdIndex := int(data[offset])
+		offset++
+		numAccounts, size, err := decodeLength(data[offset:])
+		if err != nil {
+			return nil, 0, fmt.Errorf("decode instruction %d accounts length error,err=%v", i, err)
+		}
+		offset += size
+		if len(data) < offset+numAccounts {
+			return nil, 0, fmt.Errorf("message data is too short for instruction %d accounts", i)
+		}
+		accounts := make([]int, numAccounts)
+		for j := 0; j < numAccounts; j++ {
+			accounts[j] = int(data[offset+j])
+		}
+		offset += numAccounts
+		dataLength, size, err := decodeLength(data[offset:])
+		if err != nil {
+			return nil, 0, fmt.Errorf("decode instruction %d data length error,err=%v", i, err)
+		}
+		offset += size
+		if len(data) < offset+dataLength {
+			return nil, 0, fmt.Errorf("message data is too short for instruction %d data", i)
+		}
+		instructions = append(instructions, &CompiledInstruction{
+			ProgramIdIndex: programIdIndex,
+			Accounts:       accounts,
+			Data:           append([]byte{}, data[offset:offset+dataLength]...),
+		})
+		offset += dataLength
+	}
+	return &Message{
+		Header:          header,
+		AccountKeys:     accountKeys,
+		RecentBlockHash: recentBlockHash,
+		Instructions:    instructions,
+	}, offset, nil
+}
+
+/*
+检查header与各个索引是否越界
+*/
+func (message *Message) validate() error {
+	if err := validateHeader(message.Header, len(message.AccountKeys)); err != nil {
+		return err
+	}
+	return validateInstructions(message.Instructions, len(message.AccountKeys))
+}
+
+func validateHeader(header *MessageHeader, numStaticKeys int) error {
+	if header == nil {
+		return errors.New("message header is null")
+	}
+	if header.NumRequiredSignatures < 0 || header.NumReadonlySignedAccounts < 0 || header.NumReadonlyUnsignedAccounts < 0 {
+		return errors.New("message header is not valid")
+	}
+	if header.NumRequiredSignatures > numStaticKeys {
+		return errors.New("message required signatures is big than account keys")
+	}
+	if header.NumReadonlySignedAccounts > header.NumRequiredSignatures {
+		return errors.New("message readonly signed accounts is big than required signatures")
+	}
+	if header.NumReadonlyUnsignedAccounts > numStaticKeys-header.NumRequiredSignatures {
+		return errors.New("message readonly unsigned accounts is big than unsigned accounts")
+	}
+	return nil
+}
+
+func validateInstructions(instructions []*CompiledInstruction, numKeys int) error {
+	for i, ins := range instructions {
+		if ins.ProgramIdIndex < 0 || ins.ProgramIdIndex >= numKeys {
+			return fmt.Errorf("instruction %d program id index %d out of range", i, ins.ProgramIdIndex)
+		}
+		for _, a := range ins.Accounts {
+			if a < 0 || a >= numKeys {
+				return fmt.Errorf("instruction %d account index %d out of range", i, a)
+			}
+		}
+	}
+	return nil
 }
@@ -0,0 +1,316 @@
+package transaction
+
+/*
+func：
+author： flynn
+date: 2020-08-03
+fork: https://github.com/solana-labs/solana-web3.js/src/message/v0.ts
+*/
+import (
+	"errors"
+	"fmt"
+	"github.com/JFJun/solana-go/account"
+)
+
+/*
+versioned message的前缀标记，最高位为1，低7位为版本号
+*/
+const VersionPrefixMask = 0x80
+
+type MessageVersion int
+
+const (
+	MessageVersionLegacy MessageVersion = iota
+	MessageVersion0
+)
+
+/*
+地址查找表（address lookup table）账户：表地址以及表中存储的地址
+*/
+type AddressLookupTableAccount struct {
+	Key       account.PublicKey
+	Addresses []account.PublicKey
+}
+
+type MessageAddressTableLookup struct {
+	AccountKey      account.PublicKey
+	WritableIndexes []uint8
+	ReadonlyIndexes []uint8
+}
+
+/*
+v0 message：在legacy message的基础上增加地址查找表
+账户顺序为 StaticAccountKeys + 所有查找表的可写地址 + 所有查找表的只读地址
+*/
+type MessageV0 struct {
+	Header              *MessageHeader
+	StaticAccountKeys   []account.PublicKey
+	RecentBlockHash     string
+	Instructions        []*CompiledInstruction
+	AddressTableLookups []*MessageAddressTableLookup
+}
+
+func (message *MessageV0) numLookupAccounts() (int, int) {
+	var numWritable, numReadonly int
+	for _, l := range message.AddressTableLookups {
+		numWritable += len(l.WritableIndexes)
+		numReadonly += len(l.ReadonlyIndexes)
+	}
+	return numWritable, numReadonly
+}
+
+func (message *MessageV0) IsAccountSigner(index int) bool {
+	return index < message.Header.NumRequiredSignatures
+}
+
+func (message *MessageV0) IsAccountWritable(index int) bool {
+	numStatic := len(message.StaticAccountKeys)
+	if index < numStatic {
+		numSigned := message.Header.NumRequiredSignatures
+		if index < numSigned {
+			return index < numSigned-message.Header.NumReadonlySignedAccounts
+		}
+		return index < numStatic-message.Header.NumReadonlyUnsignedAccounts
+	}
+	numWritable, _ := message.numLookupAccounts()
+	return index-numStatic < numWritable
+}
+
+/*
+通过查找表还原完整的账户列表
+*/
+func (message *MessageV0) ResolveAccountKeys(lookupTables []*AddressLookupTableAccount) ([]account.PublicKey, error) {
+	var writable, readonly []account.PublicKey
+	for _, l := range message.AddressTableLookups {
+		var table *AddressLookupTableAccount
+		for _, t := range lookupTables {
+			if t.Key == l.AccountKey {
+				table = t
+				break
+			}
+		}
+		if table == nil {
+			return nil, fmt.Errorf("address lookup table [%s] is not found", l.AccountKey.String())
+		}
+		for _, idx := range l.WritableIndexes {
+			if int(idx) >= len(table.Addresses) {
+				return nil, fmt.Errorf("address lookup table [%s] index %d out of range", l.AccountKey.String(), idx)
+			}
+			writable = append(writable, table.Addresses[idx])
+		}
+		for _, idx := range l.ReadonlyIndexes {
+			if int(idx) >= len(table.Addresses) {
+				return nil, fmt.Errorf("address lookup table [%s] index %d out of range", l.AccountKey.String(), idx)
+			}
+			readonly = append(readonly, table.Addresses[idx])
+		}
+	}
+	var keys []account.PublicKey
+	keys = append(keys, message.StaticAccountKeys...)
+	keys = append(keys, writable...)
+	keys = append(keys, readonly...)
+	return keys, nil
+}
+
+func (message *MessageV0) Serialize() []byte {
+	legacy := &Message{
+		Header:          message.Header,
+		AccountKeys:     message.StaticAccountKeys,
+		RecentBlockHash: message.RecentBlockHash,
+		Instructions:    message.Instructions,
+	}
+	size := 1 + legacy.serializedSize() + shortvecSize(len(message.AddressTableLookups))
+	for _, l := range message.AddressTableLookups {
+		size += account.PublicKeySize + shortvecSize(len(l.WritableIndexes)) + len(l.WritableIndexes) +
+			shortvecSize(len(l.ReadonlyIndexes)) + len(l.ReadonlyIndexes)
+	}
+	data := make([]byte, 0, size)
+	data = append(data, byte(VersionPrefixMask|0))
+	data = legacy.appendTo(data)
+	data = appendLength(data, len(message.AddressTableLookups))
+	for _, l := range message.AddressTableLookups {
+		data = append(data, l.AccountKey[:]...)
+		data = appendLength(data, len(l.WritableIndexes))
+		data = append(data, l.WritableIndexes...)
+		data = appendLength(data, len(l.ReadonlyIndexes))
+		data = append(data, l.ReadonlyIndexes...)
+	}
+	return data
+}
+
+/*
+解析序列化后的v0 message
+*/
+func DeserializeMessageV0(data []byte) (*MessageV0, error) {
+	if len(data) == 0 || data[0]&VersionPrefixMask == 0 {
+		return nil, errors.New("message is not versioned")
+	}
+	if version := data[0] &^ VersionPrefixMask; version != 0 {
+		return nil, fmt.Errorf("message version %d is not supported", version)
+	}
+	legacy, size, err := decodeMessage(data[1:])
+	if err != nil {
+		return nil, err
+	}
+	offset := 1 + size
+	numLookups, size, err := decodeLength(data[offset:])
+	if err != nil {
+		return nil, fmt.Errorf("decode address table lookups length error,err=%v", err)
+	}
+	offset += size
+	var lookups []*MessageAddressTableLookup
+	for i := 0; i < numLookups; i++ {
+		if len(data) < offset+account.PublicKeySize {
+			return nil, fmt.Errorf("message data is too short for address table lookup %d", i)
+		}
+		lookup := new(MessageAddressTableLookup)
+		copy(lookup.AccountKey[:], data[offset:offset+account.PublicKeySize])
+		offset += account.PublicKeySize
+		for _, indexes := range []*[]uint8{&lookup.WritableIndexes, &lookup.ReadonlyIndexes} {
+			n, size, err := decodeLength(data[offset:])
+			if err != nil {
+				return nil, fmt.Errorf("decode address table lookup %d indexes length error,err=%v", i, err)
+			}
+			offset += size
+			if len(data) < offset+n {
+				return nil, fmt.Errorf("message data is too short for address table lookup %d indexes", i)
+			}
+			*indexes = make([]uint8, n)
+			copy(*indexes, data[offset:offset+n])
+			offset += n
+		}
+		lookups = append(lookups, lookup)
+	}
+	if offset != len(data) {
+		return nil, fmt.Errorf("message data has %d unexpected trailing bytes", len(data)-offset)
+	}
+	message := &MessageV0{
+		Header:              legacy.Header,
+		StaticAccountKeys:   legacy.AccountKeys,
+		RecentBlockHash:     legacy.RecentBlockHash,
+		Instructions:        legacy.Instructions,
+		AddressTableLookups: lookups,
+	}
+	if err := message.validate(); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+/*
+检查header与各个索引是否越界，program id不能来自查找表
+*/
+func (message *MessageV0) validate() error {
+	if err := validateHeader(message.Header, len(message.StaticAccountKeys)); err != nil {
+		return err
+	}
+	numWritable, numReadonly := message.numLookupAccounts()
+	if err := validateInstructions(message.Instructions, len(message.StaticAccountKeys)+numWritable+numReadonly); err != nil {
+		return err
+	}
+	for i, ins := range message.Instructions {
+		if ins.ProgramIdIndex >= len(message.StaticAccountKeys) {
+			return fmt.Errorf("instruction %d program id must be static account key", i)
+		}
+	}
+	return nil
+}
+
+/*
+编译v0 message：非签名、且不是program id的账户如果存在于查找表中，则改为通过查找表索引引用
+从wire格式还原且未修改的交易直接返回原始message
+*/
+func (tx *Transaction) CompileMessageV0(lookupTables []*AddressLookupTableAccount) (*MessageV0, error) {
+	if d := tx.unmodifiedMessage(); d != nil && d.messageV0 != nil {
+		tx.setSigners(d.signers)
+		return d.messageV0, nil
+	}
+	accountMetas, err := tx.compileAccountMetas()
+	if err != nil {
+		return nil, err
+	}
+	invoked := make(map[account.PublicKey]bool)
+	for _, ins := range tx.Instructions {
+		invoked[ins.GetProgramId()] = true
+	}
+	drained := make(map[account.PublicKey]bool)
+	var (
+		lookups                        []*MessageAddressTableLookup
+		writableLoaded, readonlyLoaded []account.PublicKey
+	)
+	for _, table := range lookupTables {
+		tableIndex := make(map[account.PublicKey]uint8)
+		for i, addr := range table.Addresses {
+			if i > 255 {
+				break
+			}
+			if _, ok := tableIndex[addr]; !ok {
+				tableIndex[addr] = uint8(i)
+			}
+		}
+		lookup := &MessageAddressTableLookup{AccountKey: table.Key}
+		var writable, readonly []account.PublicKey
+		for _, meta := range accountMetas {
+			if meta.IsSigner || invoked[meta.PubKey] || drained[meta.PubKey] {
+				continue
+			}
+			idx, ok := tableIndex[meta.PubKey]
+			if !ok {
+				continue
+			}
+			if meta.IsWriteable {
+				lookup.WritableIndexes = append(lookup.WritableIndexes, idx)
+				writable = append(writable, meta.PubKey)
+			} else {
+				lookup.ReadonlyIndexes = append(lookup.ReadonlyIndexes, idx)
+				readonly = append(readonly, meta.PubKey)
+			}
+			drained[meta.PubKey] = true
+		}
+		if len(writable)+len(readonly) > 0 {
+			lookups = append(lookups, lookup)
+			writableLoaded = append(writableLoaded, writable...)
+			readonlyLoaded = append(readonlyLoaded, readonly...)
+		}
+	}
+
+	var (
+		numReadonlySignedAccounts, numReadonlyUnsignedAccounts, numSigned int
+		staticKeys, accountKeys                                           []account.PublicKey
+	)
+	for _, u := range accountMetas {
+		if drained[u.PubKey] {
+			continue
+		}
+		if u.IsSigner {
+			if numSigned > 0 && !u.IsWriteable {
+				numReadonlySignedAccounts++
+			}
+			numSigned++
+		} else if !u.IsWriteable {
+			numReadonlyUnsignedAccounts++
+		}
+		staticKeys = append(staticKeys, u.PubKey)
+	}
+	accountKeys = append(accountKeys, staticKeys...)
+	accountKeys = append(accountKeys, writableLoaded...)
+	accountKeys = append(accountKeys, readonlyLoaded...)
+	if len(accountKeys) > 256 {
+		return nil, fmt.Errorf("message account keys count [%d] is big than 256", len(accountKeys))
+	}
+	instructions, err := compileInstructions(tx.Instructions, accountKeys)
+	if err != nil {
+		return nil, err
+	}
+	return &MessageV0{
+		Header: &MessageHeader{
+			NumRequiredSignatures:       numSigned,
+			NumReadonlySignedAccounts:   numReadonlySignedAccounts,
+			NumReadonlyUnsignedAccounts: numReadonlyUnsignedAccounts,
+		},
+		StaticAccountKeys:   staticKeys,
+		RecentBlockHash:     tx.RecentBlockHash,
+		Instructions:        instructions,
+		AddressTableLookups: lookups,
+	}, nil
+}
@@ -0,0 +1,74 @@
+package transaction
+
+/*
+func：
+author： flynn
+date: 2020-08-03
+fork: https://github.com/solana-labs/solana-web3.js/src/system-program.js
+*/
+import (
+	"errors"
+	"github.com/JFJun/solana-go/account"
+	"github.com/JFJun/solana-go/systemprogram"
+)
+
+type CreateNonceAccountParams struct {
+	From       account.PublicKey
+	Nonce      account.PublicKey
+	Authorized account.PublicKey
+	Lamports   uint64 // 一般为NonceAccountLength的免租金额
+}
+
+/*
+创建并初始化nonce账户，需要From和Nonce两个账户签名
+*/
+func NewCreateNonceAccount(params CreateNonceAccountParams) ([]ITransactionInstruction, error) {
+	create, err := NewCreateAccount(CreateAccountParams{
+		From:       params.From,
+		NewAccount: params.Nonce,
+		Lamports:   params.Lamports,
+		Space:      systemprogram.NonceAccountLength,
+		ProgramId:  systemprogram.ProgramId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	initialize, err := NewInitializeNonceAccount(InitializeNonceAccountParams{
+		Nonce:      params.Nonce,
+		Authorized: params.Authorized,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return []ITransactionInstruction{create, initialize}, nil
+}
+
+/*
+nonceValue为nonce账户中存储的blockhash，可以通过systemprogram.GetNonceAccount获取
+*/
+func NewNonceInformation(nonce, authorized account.PublicKey, nonceValue string) (*NonceInformation, error) {
+	if nonceValue == "" {
+		return nil, errors.New("nonce value is null")
+	}
+	advance, err := NewAdvanceNonceAccount(AdvanceNonceAccountParams{
+		Nonce:      nonce,
+		Authorized: authorized,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &NonceInformation{
+		Nonce:            nonceValue,
+		NonceInstruction: advance,
+	}, nil
+}
+
+/*
+使用durable nonce代替recent block hash的交易，不会在约150个区块后过期
+编译时AdvanceNonceAccount会被放在第一条指令
+*/
+func NewNonceTransaction(nonceInfo *NonceInformation) *Transaction {
+	tx := NewTransaction(nonceInfo.Nonce)
+	tx.NonceInfo = nonceInfo
+	return tx
+}
@@ -0,0 +1,157 @@
+package transaction
+
+/*
+func：
+author： flynn
+date: 2020-08-03
+fork: https://github.com/solana-labs/solana-web3.js/src/system-program.js
+*/
+import (
+	"bytes"
+	"encoding/binary"
+	"github.com/JFJun/solana-go/account"
+	"github.com/JFJun/solana-go/systemprogram"
+	"github.com/JFJun/solana-go/sysvar"
+)
+
+type CreateAccountParams struct {
+	From       account.PublicKey
+	NewAccount account.PublicKey
+	Lamports   uint64
+	Space      uint64
+	ProgramId  account.PublicKey
+}
+
+type AssignParams struct {
+	Account   account.PublicKey
+	ProgramId account.PublicKey
+}
+
+type AllocateParams struct {
+	Account account.PublicKey
+	Space   uint64
+}
+
+type InitializeNonceAccountParams struct {
+	Nonce      account.PublicKey
+	Authorized account.PublicKey
+}
+
+type AdvanceNonceAccountParams struct {
+	Nonce      account.PublicKey
+	Authorized account.PublicKey
+}
+
+type WithdrawNonceAccountParams struct {
+	Nonce      account.PublicKey
+	Authorized account.PublicKey
+	To         account.PublicKey
+	Lamports   uint64
+}
+
+type AuthorizeNonceAccountParams struct {
+	Nonce         account.PublicKey
+	Authorized    account.PublicKey
+	NewAuthorized account.PublicKey
+}
+
+type UpgradeNonceAccountParams struct {
+	Nonce account.PublicKey
+}
+
+func NewCreateAccount(params CreateAccountParams) (ITransactionInstruction, error) {
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, systemprogram.InstructionCreateAccount)
+	binary.Write(buf, binary.LittleEndian, params.Lamports)
+	binary.Write(buf, binary.LittleEndian, params.Space)
+	buf.Write(params.ProgramId[:])
+	return newSystemInstruction([]*AccountMeta{
+		{params.From, true, true},
+		{params.NewAccount, true, true},
+	}, buf.Bytes())
+}
+
+func NewAssign(params AssignParams) (ITransactionInstruction, error) {
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, systemprogram.InstructionAssign)
+	buf.Write(params.ProgramId[:])
+	return newSystemInstruction([]*AccountMeta{
+		{params.Account, true, true},
+	}, buf.Bytes())
+}
+
+func NewAllocate(params AllocateParams) (ITransactionInstruction, error) {
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, systemprogram.InstructionAllocate)
+	binary.Write(buf, binary.LittleEndian, params.Space)
+	return newSystemInstruction([]*AccountMeta{
+		{params.Account, true, true},
+	}, buf.Bytes())
+}
+
+func NewInitializeNonceAccount(params InitializeNonceAccountParams) (ITransactionInstruction, error) {
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, systemprogram.InstructionInitializeNonceAccount)
+	buf.Write(params.Authorized[:])
+	return newSystemInstruction([]*AccountMeta{
+		{params.Nonce, false, true},
+		{sysvar.RecentBlockhashesPubkey, false, false},
+		{sysvar.RentPubkey, false, false},
+	}, buf.Bytes())
+}
+
+func NewAdvanceNonceAccount(params AdvanceNonceAccountParams) (ITransactionInstruction, error) {
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, systemprogram.InstructionAdvanceNonceAccount)
+	return newSystemInstruction([]*AccountMeta{
+		{params.Nonce, false, true},
+		{sysvar.RecentBlockhashesPubkey, false, false},
+		{params.Authorized, true, false},
+	}, buf.Bytes())
+}
+
+func NewWithdrawNonceAccount(params WithdrawNonceAccountParams) (ITransactionInstruction, error) {
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, systemprogram.InstructionWithdrawNonceAccount)
+	binary.Write(buf, binary.LittleEndian, params.Lamports)
+	return newSystemInstruction([]*AccountMeta{
+		{params.Nonce, false, true},
+		{params.To, false, true},
+		{sysvar.RecentBlockhashesPubkey, false, false},
+		{sysvar.RentPubkey, false, false},
+		{params.Authorized, true, false},
+	}, buf.Bytes())
+}
+
+func NewAuthorizeNonceAccount(params AuthorizeNonceAccountParams) (ITransactionInstruction, error) {
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, systemprogram.InstructionAuthorizeNonceAccount)
+	buf.Write(params.NewAuthorized[:])
+	return newSystemInstruction([]*AccountMeta{
+		{params.Nonce, false, true},
+		{params.Authorized, true, false},
+	}, buf.Bytes())
+}
+
+/*
+将旧版本(legacy)的nonce账户升级为当前版本
+*/
+func NewUpgradeNonceAccount(params UpgradeNonceAccountParams) (ITransactionInstruction, error) {
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, systemprogram.InstructionUpgradeNonceAccount)
+	return newSystemInstruction([]*AccountMeta{
+		{params.Nonce, false, true},
+	}, buf.Bytes())
+}
+
+/*
+bincode编码的String：u64长度 + utf8字节
+*/
+func writeRustString(buf *bytes.Buffer, s string) {
+	binary.Write(buf, binary.LittleEndian, uint64(len(s)))
+	buf.WriteString(s)
+}
+
+func newSystemInstruction(keys []*AccountMeta, data []byte) (ITransactionInstruction, error) {
+	return NewTransactionInstruction(systemprogram.ProgramId, keys, data)
+}
@@ -0,0 +1,118 @@
+package transaction
+
+/*
+func：
+author： flynn
+date: 2020-08-03
+fork: https://github.com/solana-labs/solana-web3.js/src/system-program.js
+*/
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"github.com/JFJun/solana-go/account"
+	"github.com/JFJun/solana-go/systemprogram"
+)
+
+type CreateAccountWithSeedParams struct {
+	From       account.PublicKey
+	NewAccount account.PublicKey
+	Base       account.PublicKey
+	Seed       string
+	Lamports   uint64
+	Space      uint64
+	ProgramId  account.PublicKey
+}
+
+type AllocateWithSeedParams struct {
+	Account   account.PublicKey
+	Base      account.PublicKey
+	Seed      string
+	Space     uint64
+	ProgramId account.PublicKey
+}
+
+type AssignWithSeedParams struct {
+	Account   account.PublicKey
+	Base      account.PublicKey
+	Seed      string
+	ProgramId account.PublicKey
+}
+
+type TransferWithSeedParams struct {
+	From      account.PublicKey
+	Base      account.PublicKey
+	To        account.PublicKey
+	Lamports  uint64
+	Seed      string
+	ProgramId account.PublicKey // from账户的owner
+}
+
+func NewCreateAccountWithSeed(params CreateAccountWithSeedParams) (ITransactionInstruction, error) {
+	if len(params.Seed) > account.MaxSeedLength {
+		return nil, errors.New("seed length is big than max seed length")
+	}
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, systemprogram.InstructionCreateAccountWithSeed)
+	buf.Write(params.Base[:])
+	writeRustString(buf, params.Seed)
+	binary.Write(buf, binary.LittleEndian, params.Lamports)
+	binary.Write(buf, binary.LittleEndian, params.Space)
+	buf.Write(params.ProgramId[:])
+
+	keys := []*AccountMeta{
+		{params.From, true, true},
+		{params.NewAccount, false, true},
+	}
+	if params.Base != params.From {
+		keys = append(keys, &AccountMeta{params.Base, true, false})
+	}
+	return newSystemInstruction(keys, buf.Bytes())
+}
+
+func NewAllocateWithSeed(params AllocateWithSeedParams) (ITransactionInstruction, error) {
+	if len(params.Seed) > account.MaxSeedLength {
+		return nil, errors.New("seed length is big than max seed length")
+	}
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, systemprogram.InstructionAllocateWithSeed)
+	buf.Write(params.Base[:])
+	writeRustString(buf, params.Seed)
+	binary.Write(buf, binary.LittleEndian, params.Space)
+	buf.Write(params.ProgramId[:])
+	return newSystemInstruction([]*AccountMeta{
+		{params.Account, false, true},
+		{params.Base, true, false},
+	}, buf.Bytes())
+}
+
+func NewAssignWithSeed(params AssignWithSeedParams) (ITransactionInstruction, error) {
+	if len(params.Seed) > account.MaxSeedLength {
+		return nil, errors.New("seed length is big than max seed length")
+	}
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, systemprogram.InstructionAssignWithSeed)
+	buf.Write(params.Base[:])
+	writeRustString(buf, params.Seed)
+	buf.Write(params.ProgramId[:])
+	return newSystemInstruction([]*AccountMeta{
+		{params.Account, false, true},
+		{params.Base, true, false},
+	}, buf.Bytes())
+}
+
+func NewTransferWithSeed(params TransferWithSeedParams) (ITransactionInstruction, error) {
+	if len(params.Seed) > account.MaxSeedLength {
+		return nil, errors.New("seed length is big than max seed length")
+	}
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, systemprogram.InstructionTransferWithSeed)
+	binary.Write(buf, binary.LittleEndian, params.Lamports)
+	writeRustString(buf, params.Seed)
+	buf.Write(params.ProgramId[:])
+	return newSystemInstruction([]*AccountMeta{
+		{params.From, false, true},
+		{params.Base, true, false},
+		{params.To, false, true},
+	}, buf.Bytes())
+}
@@ -13,17 +13,26 @@ import (
 	"fmt"
 	"github.com/JFJun/solana-go/account"
 	"github.com/btcsuite/btcutil/base58"
+	"sort"
 )
 
 type AccountMeta struct {
-	PubKey      []byte //
+	PubKey      account.PublicKey //
 	IsSigner    bool
 	IsWriteable bool
 }
 
+func NewAccountMeta(pubKey account.PublicKey, isSigner, isWriteable bool) *AccountMeta {
+	return &AccountMeta{
+		PubKey:      pubKey,
+		IsSigner:    isSigner,
+		IsWriteable: isWriteable,
+	}
+}
+
 type SignaturePubkeyPair struct {
 	Signature []byte
-	PublicKey []byte
+	PublicKey account.PublicKey
 }
 type TransactionCtorFields struct {
 	RecentBlockHash string
@@ -41,6 +50,45 @@ type Transaction struct {
 	Instructions    []ITransactionInstruction
 	RecentBlockHash string
 	NonceInfo       *NonceInformation
+	// 手续费支付者，总是位于account keys的第一个位置；为空时使用第一个签名者
+	FeePayer account.PublicKey
+	// 为MessageVersion0时按v0 message编译，非签名账户可以放入AddressLookupTables
+	Version             MessageVersion
+	AddressLookupTables []*AddressLookupTableAccount
+	// 编译时放在指令最前（durable nonce的AdvanceNonceAccount之后），一般通过computebudget.SetComputeBudget设置
+	ComputeBudgetInstructions []ITransactionInstruction
+	// 由DeserializeTransaction/PopulateTransaction还原的交易保留原始message
+	decoded *decodedMessage
+}
+
+/*
+从wire格式还原的message，交易未修改时原样序列化，不重新编译（重新排序账户会让已有的签名失效）
+*/
+type decodedMessage struct {
+	message         *Message
+	messageV0       *MessageV0
+	data            []byte
+	signers         []account.PublicKey
+	instructions    []ITransactionInstruction
+	recentBlockHash string
+	feePayer        account.PublicKey
+}
+
+/*
+指令、recent block hash、手续费支付者都未修改，并且没有设置nonce和compute budget指令时，返回还原时的message
+*/
+func (tx *Transaction) unmodifiedMessage() *decodedMessage {
+	d := tx.decoded
+	if d == nil || tx.NonceInfo != nil || len(tx.ComputeBudgetInstructions) > 0 ||
+		tx.RecentBlockHash != d.recentBlockHash || tx.FeePayer != d.feePayer || len(tx.Instructions) != len(d.instructions) {
+		return nil
+	}
+	for i, in := range tx.Instructions {
+		if in != d.instructions[i] {
+			return nil
+		}
+	}
+	return d
 }
 
 func NewTransaction(recentBlockHash string) *Transaction {
@@ -52,210 +100,366 @@ func (tx *Transaction) SetInstructions(itf ITransactionInstruction) {
 	tx.Instructions = append(tx.Instructions, itf)
 }
 
+/*
+使用全部签名账户签名，accounts中第一个账户为手续费支付者；会重置已有的签名
+*/
 func (tx *Transaction) Sign(accounts []*account.Account) error {
 	if len(accounts) == 0 {
 		return errors.New("do not set account")
 	}
-	var signatures []*SignaturePubkeyPair
+	var (
+		signatures []*SignaturePubkeyPair
+		hasPayer   bool
+	)
 	for _, acc := range accounts {
 		spp := new(SignaturePubkeyPair)
-		spp.PublicKey = acc.PublicKey
+		spp.PublicKey = acc.GetPublicKey()
 		signatures = append(signatures, spp)
+		if spp.PublicKey == tx.FeePayer {
+			hasPayer = true
+		}
+	}
+	if !tx.FeePayer.IsZero() && !hasPayer {
+		return fmt.Errorf("fee payer [%s] is not in sign accounts", tx.FeePayer.String())
 	}
 	tx.Signatures = signatures
-	signData, err := tx.serializeMessage()
+	return tx.PartialSign(accounts)
+}
 
+/*
+部分签名：只使用给定的账户签名，保留其他签名者已有的签名，用于多方或离线签名
+从wire格式还原且未修改的交易对原始message签名，与其他签名者签名的数据一致
+*/
+func (tx *Transaction) PartialSign(accounts []*account.Account) error {
+	if len(accounts) == 0 {
+		return errors.New("do not set account")
+	}
+	signData, err := tx.SerializeMessage()
 	if err != nil {
 		return fmt.Errorf("serial sign message error,Err==%v", err)
 	}
-	for idx, acc := range accounts {
-		priv := ed25519.NewKeyFromSeed(acc.SecretKey)
-		sig := ed25519.Sign(priv, signData)
+	for _, acc := range accounts {
+		sig := acc.Sign(signData)
 		if len(sig) != 64 {
 			return errors.New("sign data length is not equal 64")
 		}
-		tx.Signatures[idx].Signature = sig
+		if err := tx.setSignature(acc.GetPublicKey(), sig); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
-func (tx *Transaction) serializeMessage() ([]byte, error) {
-	message, err := tx.CompileMessage()
+/*
+添加外部签名（例如离线设备对SerializeMessage结果的签名），从wire格式还原的交易SerializeMessage返回原始message
+*/
+func (tx *Transaction) AddSignature(pubkey account.PublicKey, signature []byte) error {
+	if len(signature) != ed25519.SignatureSize {
+		return fmt.Errorf("signature length is %d, not equal %d", len(signature), ed25519.SignatureSize)
+	}
+	if _, err := tx.SerializeMessage(); err != nil {
+		return fmt.Errorf("serial sign message error,Err==%v", err)
+	}
+	sig := make([]byte, ed25519.SignatureSize)
+	copy(sig, signature)
+	return tx.setSignature(pubkey, sig)
+}
+
+func (tx *Transaction) setSignature(pubkey account.PublicKey, signature []byte) error {
+	for _, s := range tx.Signatures {
+		if s.PublicKey == pubkey {
+			s.Signature = signature
+			return nil
+		}
+	}
+	return fmt.Errorf("account [%s] is not a signer of this transaction", pubkey.String())
+}
+
+/*
+返回还未签名的签名账户
+*/
+func (tx *Transaction) MissingSigners() ([]account.PublicKey, error) {
+	if _, err := tx.SerializeMessage(); err != nil {
+		return nil, err
+	}
+	var missing []account.PublicKey
+	for _, s := range tx.Signatures {
+		if s.Signature == nil {
+			missing = append(missing, s.PublicKey)
+		}
+	}
+	return missing, nil
+}
+
+type SignatureVerifyResult struct {
+	PublicKey account.PublicKey
+	Signed    bool // 是否已签名
+	Valid     bool // 签名是否有效
+}
+
+/*
+逐个校验签名者的签名，未签名的签名者Valid为false
+从wire格式还原且未修改的交易按原始message数据校验
+*/
+func (tx *Transaction) VerifySignatures() ([]*SignatureVerifyResult, error) {
+	signData, err := tx.SerializeMessage()
 	if err != nil {
 		return nil, err
 	}
-	return message.Serialize(), nil
+	return tx.verifySignatures(signData), nil
 }
-func (tx *Transaction) CompileMessage() (*Message, error) {
+
+func (tx *Transaction) verifySignatures(signData []byte) []*SignatureVerifyResult {
+	var results []*SignatureVerifyResult
+	for _, s := range tx.Signatures {
+		results = append(results, &SignatureVerifyResult{
+			PublicKey: s.PublicKey,
+			Signed:    s.Signature != nil,
+			Valid:     s.Signature != nil && account.VerifyMessage(s.PublicKey, signData, s.Signature),
+		})
+	}
+	return results
+}
+
+/*
+返回待签名的message数据，可交给外部签名者签名
+*/
+func (tx *Transaction) SerializeMessage() ([]byte, error) {
+	return tx.serializeMessage()
+}
+
+func (tx *Transaction) serializeMessage() ([]byte, error) {
+	if d := tx.unmodifiedMessage(); d != nil {
+		tx.setSigners(d.signers)
+		return d.data, nil
+	}
+	switch tx.Version {
+	case MessageVersionLegacy:
+		message, err := tx.CompileMessage()
+		if err != nil {
+			return nil, err
+		}
+		return message.Serialize(), nil
+	case MessageVersion0:
+		message, err := tx.CompileMessageV0(tx.AddressLookupTables)
+		if err != nil {
+			return nil, err
+		}
+		return message.Serialize(), nil
+	default:
+		return nil, fmt.Errorf("unknown message version %d", tx.Version)
+	}
+}
+
+/*
+收集交易涉及的所有账户并去重排序，与web3.js的compileMessage一致（只用于编译builder构造的指令，从wire格式还原且未修改的交易直接使用原始message）：
+1. 按指令顺序收集账户，之后追加program id（只读）
+2. 重复的账户合并IsSigner/IsWriteable标记
+3. 排序：可写签名 / 只读签名 / 可写非签名 / 只读非签名，同一类中按base58字符串的localeCompare排序
+4. 手续费支付者移动到第一个位置
+*/
+func (tx *Transaction) compileAccountMetas() ([]*AccountMeta, error) {
+	// durable nonce交易：nonce作为recent block hash，AdvanceNonceAccount必须是第一条指令，compute budget指令紧随其后
+	var prefix []ITransactionInstruction
+	if tx.NonceInfo != nil {
+		tx.RecentBlockHash = tx.NonceInfo.Nonce
+		prefix = append(prefix, tx.NonceInfo.NonceInstruction)
+	}
+	prefix = append(prefix, tx.ComputeBudgetInstructions...)
+	if len(prefix) > 0 {
+		tx.Instructions = prependInstructions(prefix, tx.Instructions)
+	}
 	if tx.RecentBlockHash == "" {
 		return nil, errors.New("tx recent block hash is null")
 	}
+	if len(base58.Decode(tx.RecentBlockHash)) != 32 {
+		return nil, fmt.Errorf("tx recent block hash [%s] is not valid", tx.RecentBlockHash)
+	}
 	if len(tx.Instructions) < 1 {
 		return nil, errors.New("tx instruction length is less than 1")
 	}
-
-	if tx.NonceInfo != nil && tx.Instructions[0] != tx.NonceInfo.NonceInstruction {
-		tx.RecentBlockHash = tx.NonceInfo.Nonce
-		var ins []ITransactionInstruction
-		ins = append(ins, tx.NonceInfo.NonceInstruction)
-		ins = append(ins, tx.Instructions...)
-		tx.Instructions = nil
-		tx.Instructions = ins
-	}
 	var (
-		numReadonlySignedAccounts, numReadonlyUnsignedAccounts int
-		programIds                                             []string
-		accountMetas                                           []*AccountMeta
+		uniqueAccountMeta []*AccountMeta
+		metaIndex         = make(map[account.PublicKey]int)
 	)
+	// 复制一份，避免修改指令中的AccountMeta
+	addAccountMeta := func(pubkey account.PublicKey, isSigner, isWriteable bool) {
+		if idx, ok := metaIndex[pubkey]; ok {
+			uniqueAccountMeta[idx].IsSigner = uniqueAccountMeta[idx].IsSigner || isSigner
+			uniqueAccountMeta[idx].IsWriteable = uniqueAccountMeta[idx].IsWriteable || isWriteable
+			return
+		}
+		metaIndex[pubkey] = len(uniqueAccountMeta)
+		uniqueAccountMeta = append(uniqueAccountMeta, NewAccountMeta(pubkey, isSigner, isWriteable))
+	}
 	for _, in := range tx.Instructions {
-		accountMetas = append(accountMetas, in.GetKeys()...)
-		if len(programIds) == 0 {
-			programIds = append(programIds, in.GetProgramId())
+		for _, k := range in.GetKeys() {
+			addAccountMeta(k.PubKey, k.IsSigner, k.IsWriteable)
+		}
+	}
+	for _, in := range tx.Instructions {
+		addAccountMeta(in.GetProgramId(), false, false)
+	}
+	// 已有的签名者一定是签名账户
+	for _, s := range tx.Signatures {
+		if idx, ok := metaIndex[s.PublicKey]; ok {
+			uniqueAccountMeta[idx].IsSigner = true
 		} else {
-			if !isIncludes(in.GetProgramId(), programIds) {
-				programIds = append(programIds, in.GetProgramId())
+			addAccountMeta(s.PublicKey, true, true)
+		}
+	}
+	sort.SliceStable(uniqueAccountMeta, func(i, j int) bool {
+		ri, rj := accountMetaRank(uniqueAccountMeta[i]), accountMetaRank(uniqueAccountMeta[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return compareBase58(uniqueAccountMeta[i].PubKey.String(), uniqueAccountMeta[j].PubKey.String()) < 0
+	})
+
+	// 手续费支付者必须是第一个可写的签名账户
+	feePayer := tx.FeePayer
+	if feePayer.IsZero() && len(tx.Signatures) > 0 {
+		feePayer = tx.Signatures[0].PublicKey
+	}
+	if !feePayer.IsZero() {
+		metas := []*AccountMeta{NewAccountMeta(feePayer, true, true)}
+		for _, u := range uniqueAccountMeta {
+			if u.PubKey != feePayer {
+				metas = append(metas, u)
 			}
 		}
+		uniqueAccountMeta = metas
+	}
+
+	var signers []account.PublicKey
+	for _, u := range uniqueAccountMeta {
+		if !u.IsSigner {
+			break
+		}
+		signers = append(signers, u.PubKey)
 	}
-	for _, p := range programIds {
-		accountMetas = append(accountMetas, &AccountMeta{
-			base58.Decode(p),
-			false,
-			false,
+	tx.setSigners(signers)
+	return uniqueAccountMeta, nil
+}
+
+/*
+按message中签名账户的顺序整理签名，保留已有的签名
+*/
+func (tx *Transaction) setSigners(signers []account.PublicKey) {
+	existing := make(map[account.PublicKey][]byte)
+	for _, s := range tx.Signatures {
+		existing[s.PublicKey] = s.Signature
+	}
+	var signatures []*SignaturePubkeyPair
+	for _, pubkey := range signers {
+		signatures = append(signatures, &SignaturePubkeyPair{
+			existing[pubkey],
+			pubkey,
 		})
 	}
-	// 排序accountMeta
-	accountMetas = sortAccountMetas(accountMetas)
-	// 删除重复的accountMeta
-	var uniqueAccountMeta []*AccountMeta
-	if len(accountMetas) <= 1 {
-		uniqueAccountMeta = accountMetas
-	} else {
-		for _, accM := range accountMetas {
-			if len(uniqueAccountMeta) == 0 {
-				uniqueAccountMeta = append(uniqueAccountMeta, accM)
-				continue
-			}
-			isHave := false
-			for _, uAccM := range uniqueAccountMeta {
-				if bytes.Compare(uAccM.PubKey, accM.PubKey) == 0 {
-					isHave = true
-					break
-				}
-			}
-			if !isHave {
-				uniqueAccountMeta = append(uniqueAccountMeta, accM)
-			}
+	tx.Signatures = signatures
+}
 
+/*
+把prefix放在最前，rest中已有的相同指令会被移除，多次编译结果不变
+*/
+func prependInstructions(prefix, rest []ITransactionInstruction) []ITransactionInstruction {
+	instructions := append([]ITransactionInstruction{}, prefix...)
+	for _, in := range rest {
+		duplicate := false
+		for _, p := range prefix {
+			if in == p {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			instructions = append(instructions, in)
 		}
+	}
+	return instructions
+}
+
+func accountMetaRank(meta *AccountMeta) int {
+	switch {
+	case meta.IsSigner && meta.IsWriteable:
+		return 0
+	case meta.IsSigner:
+		return 1
+	case meta.IsWriteable:
+		return 2
+	default:
+		return 3
+	}
+}
 
-		////冒泡排序
-		//for i:=0;i<len(accountMetas)-1;i++{
-		//	pubkeyString:=accountMetas[i].PubKey
-		//	isHave :=false
-		//	for j:=i+1;j<len(accountMetas);j++{
-		//		if bytes.Compare(pubkeyString,accountMetas[j].PubKey)==0 {
-		//			isHave = true
-		//			break
-		//		}
-		//	}
-		//	if !isHave {
-		//		uniqueAccountMeta = append(uniqueAccountMeta,accountMetas[i])
-		//	}
-		//}
-	}
-
-	if len(tx.Signatures) > 0 {
-		for _, s := range tx.Signatures {
-			sigPubkeyString := s.PublicKey
-			isHave := false
-			for _, u := range uniqueAccountMeta {
-				if bytes.Compare(sigPubkeyString, u.PubKey) == 0 {
-					isHave = true
-					u.IsSigner = true
-					break
-				}
+/*
+等价于web3.js中的a.localeCompare(b, 'en', {sensitivity: 'variant', caseFirst: 'lower'})，只处理base58字符
+先忽略大小写比较（数字在字母之前），相同时第一个大小写不同的位置小写在前
+*/
+func compareBase58(a, b string) int {
+	primary := func(c byte) byte {
+		if c >= 'A' && c <= 'Z' {
+			return c + 'a' - 'A'
+		}
+		return c
+	}
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if pa, pb := primary(a[i]), primary(b[i]); pa != pb {
+			if pa < pb {
+				return -1
 			}
-			if !isHave {
-				uniqueAccountMeta = append([]*AccountMeta{
-					{
-						PubKey:      sigPubkeyString,
-						IsSigner:    true,
-						IsWriteable: true,
-					},
-				}, uniqueAccountMeta...)
+			return 1
+		}
+	}
+	if len(a) != len(b) {
+		if len(a) < len(b) {
+			return -1
+		}
+		return 1
+	}
+	for i := 0; i < len(a); i++ {
+		if a[i] != b[i] {
+			// 小写字母的ASCII码更大，小写在前
+			if a[i] > b[i] {
+				return -1
 			}
+			return 1
 		}
 	}
-	var signedKeys, unsignedKeys []string
-	for _, u := range uniqueAccountMeta {
+	return 0
+}
+
+func (tx *Transaction) CompileMessage() (*Message, error) {
+	if d := tx.unmodifiedMessage(); d != nil && d.message != nil {
+		tx.setSigners(d.signers)
+		return d.message, nil
+	}
+	accountMetas, err := tx.compileAccountMetas()
+	if err != nil {
+		return nil, err
+	}
+	var (
+		numReadonlySignedAccounts, numReadonlyUnsignedAccounts, numSigned int
+		accountKeys                                                       []account.PublicKey
+	)
+	for _, u := range accountMetas {
 		if u.IsSigner {
 			// Promote the first signer to writable as it is the fee payer
-			length := len(signedKeys)
-			signedKeys = append(signedKeys, base58.Encode(u.PubKey))
-			if length > 0 && !u.IsWriteable {
+			if numSigned > 0 && !u.IsWriteable {
 				numReadonlySignedAccounts++
 			}
+			numSigned++
 		} else {
-			unsignedKeys = append(unsignedKeys, base58.Encode(u.PubKey))
 			if !u.IsWriteable {
 				numReadonlyUnsignedAccounts++
 			}
 		}
+		accountKeys = append(accountKeys, u.PubKey)
 	}
-
-	// Initialize signature array, if needed
-	if len(tx.Signatures) == 0 {
-		if len(signedKeys) == 0 {
-			tx.Signatures = nil
-		}
-		var signatures []*SignaturePubkeyPair
-		for _, s := range signedKeys {
-			signatures = append(signatures, &SignaturePubkeyPair{
-				nil,
-				base58.Decode(s),
-			})
-		}
-		tx.Signatures = signatures
-	}
-	var accountKeys []string
-	accountKeys = append(accountKeys, signedKeys...)
-	accountKeys = append(accountKeys, unsignedKeys...)
-	var instructions []*CompiledInstruction
-	for _, ins := range tx.Instructions {
-		data := base58.Encode(ins.GetData())
-		program := ins.GetProgramId()
-		var (
-			programIdIndex int
-			accounts       []int
-		)
-		//for i,a:=range accountKeys{
-		//	if a==program {
-		//		programIdIndex = i
-		//	}
-		//	for _,k:=range ins.GetKeys(){
-		//
-		//		if base58.Encode(k.PubKey)==a {
-		//			accounts = append(accounts,i)
-		//		}
-		//	}
-		//}
-		for _, k := range ins.GetKeys() {
-			for i, a := range accountKeys {
-				if a == program {
-					programIdIndex = i
-				}
-				if base58.Encode(k.PubKey) == a {
-					accounts = append(accounts, i)
-				}
-			}
-		}
-
-		instructions = append(instructions, &CompiledInstruction{
-			ProgramIdIndex: programIdIndex,
-			Accounts:       accounts,
-			Data:           data,
-		})
+	instructions, err := compileInstructions(tx.Instructions, accountKeys)
+	if err != nil {
+		return nil, err
 	}
 
 	messageHeader := new(MessageHeader)
@@ -270,52 +474,92 @@ func (tx *Transaction) CompileMessage() (*Message, error) {
 	message.Instructions = instructions
 	return message, nil
 }
-func isIncludes(p string, ProgramIds []string) bool {
-	for _, pp := range ProgramIds {
-		if p == pp {
-			return true
-		}
-	}
-	return false
-}
 
-func sortAccountMetas(accountMetas []*AccountMeta) []*AccountMeta {
-	if len(accountMetas) <= 1 {
-		return accountMetas
+/*
+将指令中的账户和program id转换为accountKeys中的索引
+*/
+func compileInstructions(txInstructions []ITransactionInstruction, accountKeys []account.PublicKey) ([]*CompiledInstruction, error) {
+	keyIndex := make(map[account.PublicKey]int, len(accountKeys))
+	for i, k := range accountKeys {
+		keyIndex[k] = i
 	}
-	cmp := func(a, b *AccountMeta) bool {
-		//先判断签名
-		if !a.IsSigner && b.IsSigner {
-			return true
-		}
-		//在判断write
-		if !a.IsWriteable && b.IsWriteable {
-			return true
+	var instructions []*CompiledInstruction
+	for _, ins := range txInstructions {
+		programIdIndex, ok := keyIndex[ins.GetProgramId()]
+		if !ok {
+			return nil, fmt.Errorf("program id [%s] is not in account keys", ins.GetProgramId().String())
 		}
-		return false
-	}
-	for i := 0; i < len(accountMetas)-1; i++ {
-		for j := i + 1; j < len(accountMetas); j++ {
-			if cmp(accountMetas[i], accountMetas[j]) {
-				accountMetas[i], accountMetas[j] = accountMetas[j], accountMetas[i]
+		var accounts []int
+		for _, k := range ins.GetKeys() {
+			idx, ok := keyIndex[k.PubKey]
+			if !ok {
+				return nil, fmt.Errorf("account [%s] is not in account keys", k.PubKey.String())
 			}
+			accounts = append(accounts, idx)
 		}
+		instructions = append(instructions, &CompiledInstruction{
+			ProgramIdIndex: programIdIndex,
+			Accounts:       accounts,
+			Data:           ins.GetData(),
+		})
 	}
-	return accountMetas
+	return instructions, nil
+}
+
+type SerializeConfig struct {
+	// 要求所有签名者都已签名
+	RequireAllSignatures bool
+	// 要求已有的签名都校验通过
+	VerifySignatures bool
 }
 
 func (tx *Transaction) Serialize() ([]byte, error) {
-	if tx.Signatures == nil || len(tx.Signatures) == 0 {
-		return nil, errors.New("transaction has not been signedl")
-	}
+	return tx.SerializeWithConfig(SerializeConfig{
+		RequireAllSignatures: true,
+	})
+}
+
+/*
+未签名的位置填充64个0字节，便于把部分签名的交易交给其他签名者
+*/
+func (tx *Transaction) SerializeWithConfig(config SerializeConfig) ([]byte, error) {
 	signData, err := tx.serializeMessage()
 	if err != nil {
 		return nil, fmt.Errorf("tx serialize message error,err=%v", err)
 	}
-	signatureCount := encodeLength(len(tx.Signatures))
-	var wireTransaction []byte
-	wireTransaction = append(wireTransaction, signatureCount...)
+	if len(tx.Signatures) == 0 {
+		return nil, errors.New("transaction has not been signedl")
+	}
+	if config.RequireAllSignatures {
+		var missing []string
+		for _, sig := range tx.Signatures {
+			if sig.Signature == nil {
+				missing = append(missing, sig.PublicKey.String())
+			}
+		}
+		if len(missing) > 0 {
+			return nil, fmt.Errorf("transaction is missing signatures for %v", missing)
+		}
+	}
+	if config.VerifySignatures {
+		var invalid []string
+		for _, r := range tx.verifySignatures(signData) {
+			if r.Signed && !r.Valid {
+				invalid = append(invalid, r.PublicKey.String())
+			}
+		}
+		if len(invalid) > 0 {
+			return nil, fmt.Errorf("transaction has invalid signatures for %v", invalid)
+		}
+	}
+	wireTransaction := make([]byte, 0, shortvecSize(len(tx.Signatures))+len(tx.Signatures)*ed25519.SignatureSize+len(signData))
+	wireTransaction = appendLength(wireTransaction, len(tx.Signatures))
+	var emptySignature [ed25519.SignatureSize]byte
 	for _, sig := range tx.Signatures {
+		if sig.Signature == nil {
+			wireTransaction = append(wireTransaction, emptySignature[:]...)
+			continue
+		}
 		wireTransaction = append(wireTransaction, sig.Signature...)
 	}
 	wireTransaction = append(wireTransaction, signData...)
@@ -324,3 +568,136 @@ func (tx *Transaction) Serialize() ([]byte, error) {
 	}
 	return wireTransaction, nil
 }
+
+/*
+解析wire格式的交易（签名数量 + 签名 + message），Serialize的逆过程
+v0交易如果使用了地址查找表，需要传入对应的lookupTables
+*/
+func DeserializeTransaction(data []byte, lookupTables ...*AddressLookupTableAccount) (*Transaction, error) {
+	signatures, messageData, err := SplitTransaction(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(messageData) > 0 && messageData[0]&VersionPrefixMask != 0 {
+		message, err := DeserializeMessageV0(messageData)
+		if err != nil {
+			return nil, fmt.Errorf("deserialize message v0 error,err=%v", err)
+		}
+		return PopulateTransactionV0(message, signatures, lookupTables)
+	}
+	message, err := DeserializeMessage(messageData)
+	if err != nil {
+		return nil, fmt.Errorf("deserialize message error,err=%v", err)
+	}
+	return PopulateTransaction(message, signatures)
+}
+
+/*
+把wire格式的交易拆分为签名和message数据，不解析message
+*/
+func SplitTransaction(data []byte) ([][]byte, []byte, error) {
+	numSignatures, offset, err := decodeLength(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("decode signatures length error,err=%v", err)
+	}
+	if len(data) < offset+numSignatures*ed25519.SignatureSize {
+		return nil, nil, errors.New("transaction data is too short for signatures")
+	}
+	var signatures [][]byte
+	for i := 0; i < numSignatures; i++ {
+		sig := make([]byte, ed25519.SignatureSize)
+		copy(sig, data[offset:offset+ed25519.SignatureSize])
+		signatures = append(signatures, sig)
+		offset += ed25519.SignatureSize
+	}
+	return signatures, data[offset:], nil
+}
+
+/*
+由message和签名还原交易，全0的签名视为未签名；索引越界的message返回错误
+*/
+func PopulateTransaction(message *Message, signatures [][]byte) (*Transaction, error) {
+	if err := message.validate(); err != nil {
+		return nil, err
+	}
+	tx, err := populateTransaction(message.Header, message.AccountKeys, message.RecentBlockHash, message.Instructions,
+		message.IsAccountSigner, message.IsAccountWritable, signatures)
+	if err != nil {
+		return nil, err
+	}
+	tx.decoded = tx.newDecodedMessage(message.Serialize(), message.AccountKeys[:message.Header.NumRequiredSignatures])
+	tx.decoded.message = message
+	return tx, nil
+}
+
+func PopulateTransactionV0(message *MessageV0, signatures [][]byte, lookupTables []*AddressLookupTableAccount) (*Transaction, error) {
+	if err := message.validate(); err != nil {
+		return nil, err
+	}
+	accountKeys, err := message.ResolveAccountKeys(lookupTables)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := populateTransaction(message.Header, accountKeys, message.RecentBlockHash, message.Instructions,
+		message.IsAccountSigner, message.IsAccountWritable, signatures)
+	if err != nil {
+		return nil, err
+	}
+	tx.Version = MessageVersion0
+	tx.decoded = tx.newDecodedMessage(message.Serialize(), message.StaticAccountKeys[:message.Header.NumRequiredSignatures])
+	tx.decoded.messageV0 = message
+	for _, l := range message.AddressTableLookups {
+		for _, t := range lookupTables {
+			if t.Key == l.AccountKey {
+				tx.AddressLookupTables = append(tx.AddressLookupTables, t)
+				break
+			}
+		}
+	}
+	return tx, nil
+}
+
+func populateTransaction(header *MessageHeader, accountKeys []account.PublicKey, recentBlockHash string, instructions []*CompiledInstruction,
+	isSigner, isWritable func(int) bool, signatures [][]byte) (*Transaction, error) {
+	if len(signatures) != header.NumRequiredSignatures {
+		return nil, fmt.Errorf("signatures count [%d] is not equal required signatures [%d]", len(signatures), header.NumRequiredSignatures)
+	}
+	tx := NewTransaction(recentBlockHash)
+	if len(accountKeys) > 0 {
+		tx.FeePayer = accountKeys[0]
+	}
+	emptySignature := make([]byte, ed25519.SignatureSize)
+	for i, sig := range signatures {
+		spp := &SignaturePubkeyPair{PublicKey: accountKeys[i]}
+		if !bytes.Equal(sig, emptySignature) {
+			spp.Signature = sig
+		}
+		tx.Signatures = append(tx.Signatures, spp)
+	}
+	for _, ci := range instructions {
+		var keys []*AccountMeta
+		for _, idx := range ci.Accounts {
+			keys = append(keys, &AccountMeta{
+				PubKey:      accountKeys[idx],
+				IsSigner:    isSigner(idx),
+				IsWriteable: isWritable(idx),
+			})
+		}
+		ti := new(TransactionInstruction)
+		ti.keys = keys
+		ti.programId = accountKeys[ci.ProgramIdIndex]
+		ti.data = ci.Data
+		tx.Instructions = append(tx.Instructions, ti)
+	}
+	return tx, nil
+}
+
+func (tx *Transaction) newDecodedMessage(data []byte, signers []account.PublicKey) *decodedMessage {
+	return &decodedMessage{
+		data:            data,
+		signers:         signers,
+		instructions:    append([]ITransactionInstruction{}, tx.Instructions...),
+		recentBlockHash: tx.RecentBlockHash,
+		feePayer:        tx.FeePayer,
+	}
+}
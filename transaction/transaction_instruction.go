@@ -6,22 +6,42 @@ author： flynn
 date: 2020-08-03
 fork: https://github.com/solana-labs/solana-web3.js/src/transaction.js
 */
-import "errors"
+import (
+	"errors"
+	"github.com/JFJun/solana-go/account"
+)
 
 type ITransactionInstruction interface {
 	GetKeys() []*AccountMeta
 	SetKeys(keys []*AccountMeta) error
-	GetProgramId() string
-	SetProgramId(programId string) error
+	GetProgramId() account.PublicKey
+	SetProgramId(programId account.PublicKey) error
 	GetData() []byte
 	SetData(data []byte) error
 }
 type TransactionInstruction struct {
 	keys      []*AccountMeta
-	programId string
+	programId account.PublicKey
 	data      []byte
 }
 
+func NewTransactionInstruction(programId account.PublicKey, keys []*AccountMeta, data []byte) (ITransactionInstruction, error) {
+	ti := new(TransactionInstruction)
+	err := ti.SetKeys(keys)
+	if err != nil {
+		return nil, err
+	}
+	err = ti.SetProgramId(programId)
+	if err != nil {
+		return nil, err
+	}
+	err = ti.SetData(data)
+	if err != nil {
+		return nil, err
+	}
+	return ti, nil
+}
+
 func (ti *TransactionInstruction) GetKeys() []*AccountMeta {
 	return ti.keys
 }
@@ -33,13 +53,12 @@ func (ti *TransactionInstruction) SetKeys(keys []*AccountMeta) error {
 	ti.keys = keys
 	return nil
 }
-func (ti *TransactionInstruction) GetProgramId() string {
+func (ti *TransactionInstruction) GetProgramId() account.PublicKey {
 	return ti.programId
 }
-func (ti *TransactionInstruction) SetProgramId(programId string) error {
-	if programId == "" {
-		return errors.New("program id is null")
-	}
+
+// system program的id全为0，这里不做非空校验
+func (ti *TransactionInstruction) SetProgramId(programId account.PublicKey) error {
 	ti.programId = programId
 	return nil
 }
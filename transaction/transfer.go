@@ -11,25 +11,26 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
-	"github.com/btcsuite/btcutil/base58"
+	"github.com/JFJun/solana-go/account"
+	"github.com/JFJun/solana-go/systemprogram"
 	"math/big"
 )
 
 type TransferParams struct {
-	From   string
-	To     string
+	From   account.PublicKey
+	To     account.PublicKey
 	Amount *big.Int
 }
 
-func (tp *TransferParams) GetFromPublicKey() []byte {
-	return base58.Decode(tp.From)
+func (tp *TransferParams) GetFromPublicKey() account.PublicKey {
+	return tp.From
 }
-func (tp *TransferParams) GetToPublicKey() []byte {
-	return base58.Decode(tp.To)
+func (tp *TransferParams) GetToPublicKey() account.PublicKey {
+	return tp.To
 }
 
 func NewTransfer(transfer TransferParams) (ITransactionInstruction, error) {
-	transferIndex := uint32(2) //https://github.com/solana-labs/solana-web3.js/src/system-program.js-->p511  version:v0.64.0
+	transferIndex := systemprogram.InstructionTransfer //https://github.com/solana-labs/solana-web3.js/src/system-program.js-->p511  version:v0.64.0
 	lamports := transfer.Amount.Uint64()
 	buf1 := new(bytes.Buffer)
 	buf2 := new(bytes.Buffer)
@@ -52,7 +53,7 @@ func NewTransfer(transfer TransferParams) (ITransactionInstruction, error) {
 		{transfer.GetFromPublicKey(), true, true},
 		{transfer.GetToPublicKey(), false, true},
 	})
-	err = ti.SetProgramId("11111111111111111111111111111111")
+	err = ti.SetProgramId(systemprogram.ProgramId)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,142 @@
+package vote
+
+/*
+func：
+author： flynn
+date: 2020-08-03
+fork: https://github.com/solana-labs/solana-web3.js/src/programs/vote.ts
+*/
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"github.com/JFJun/solana-go/account"
+	"github.com/JFJun/solana-go/sysvar"
+	"github.com/JFJun/solana-go/transaction"
+)
+
+/*
+vote program的id
+*/
+var ProgramId = account.MustPublicKeyFromBase58("Vote111111111111111111111111111111111111111")
+
+/*
+vote program指令序号，与solana_program::vote::instruction::VoteInstruction的顺序一致
+*/
+const (
+	InstructionInitializeAccount uint32 = iota
+	InstructionAuthorize
+	InstructionVote
+	InstructionWithdraw
+	InstructionUpdateValidatorIdentity
+	InstructionUpdateCommission
+	InstructionVoteSwitch
+	InstructionAuthorizeChecked
+)
+
+type VoteAuthorize uint32
+
+const (
+	VoteAuthorizeVoter VoteAuthorize = iota
+	VoteAuthorizeWithdrawer
+)
+
+type WithdrawParams struct {
+	VotePubkey       account.PublicKey
+	AuthorizedPubkey account.PublicKey // withdraw authority
+	ToPubkey         account.PublicKey
+	Lamports         uint64
+}
+
+type UpdateCommissionParams struct {
+	VotePubkey       account.PublicKey
+	AuthorizedPubkey account.PublicKey // withdraw authority
+	Commission       uint8             // 百分比
+}
+
+/*
+新的identity也需要签名
+*/
+type UpdateValidatorIdentityParams struct {
+	VotePubkey       account.PublicKey
+	AuthorizedPubkey account.PublicKey // withdraw authority
+	NodePubkey       account.PublicKey
+}
+
+type AuthorizeParams struct {
+	VotePubkey       account.PublicKey
+	AuthorizedPubkey account.PublicKey
+	NewAuthorized    account.PublicKey
+	VoteAuthorize    VoteAuthorize
+}
+
+func NewWithdraw(params WithdrawParams) (transaction.ITransactionInstruction, error) {
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, InstructionWithdraw)
+	binary.Write(buf, binary.LittleEndian, params.Lamports)
+	return newVoteInstruction([]*transaction.AccountMeta{
+		transaction.NewAccountMeta(params.VotePubkey, false, true),
+		transaction.NewAccountMeta(params.ToPubkey, false, true),
+		transaction.NewAccountMeta(params.AuthorizedPubkey, true, false),
+	}, buf.Bytes())
+}
+
+func NewUpdateCommission(params UpdateCommissionParams) (transaction.ITransactionInstruction, error) {
+	if params.Commission > 100 {
+		return nil, fmt.Errorf("commission [%d] is greater than 100", params.Commission)
+	}
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, InstructionUpdateCommission)
+	buf.WriteByte(params.Commission)
+	return newVoteInstruction([]*transaction.AccountMeta{
+		transaction.NewAccountMeta(params.VotePubkey, false, true),
+		transaction.NewAccountMeta(params.AuthorizedPubkey, true, false),
+	}, buf.Bytes())
+}
+
+func NewUpdateValidatorIdentity(params UpdateValidatorIdentityParams) (transaction.ITransactionInstruction, error) {
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, InstructionUpdateValidatorIdentity)
+	return newVoteInstruction([]*transaction.AccountMeta{
+		transaction.NewAccountMeta(params.VotePubkey, false, true),
+		transaction.NewAccountMeta(params.NodePubkey, true, false),
+		transaction.NewAccountMeta(params.AuthorizedPubkey, true, false),
+	}, buf.Bytes())
+}
+
+func NewAuthorize(params AuthorizeParams) (transaction.ITransactionInstruction, error) {
+	if params.VoteAuthorize > VoteAuthorizeWithdrawer {
+		return nil, fmt.Errorf("vote authorize [%d] is not valid", params.VoteAuthorize)
+	}
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, InstructionAuthorize)
+	buf.Write(params.NewAuthorized[:])
+	binary.Write(buf, binary.LittleEndian, params.VoteAuthorize)
+	return newVoteInstruction([]*transaction.AccountMeta{
+		transaction.NewAccountMeta(params.VotePubkey, false, true),
+		transaction.NewAccountMeta(sysvar.ClockPubkey, false, false),
+		transaction.NewAccountMeta(params.AuthorizedPubkey, true, false),
+	}, buf.Bytes())
+}
+
+/*
+与Authorize相同，但新的authority也需要签名
+*/
+func NewAuthorizeChecked(params AuthorizeParams) (transaction.ITransactionInstruction, error) {
+	if params.VoteAuthorize > VoteAuthorizeWithdrawer {
+		return nil, fmt.Errorf("vote authorize [%d] is not valid", params.VoteAuthorize)
+	}
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, InstructionAuthorizeChecked)
+	binary.Write(buf, binary.LittleEndian, params.VoteAuthorize)
+	return newVoteInstruction([]*transaction.AccountMeta{
+		transaction.NewAccountMeta(params.VotePubkey, false, true),
+		transaction.NewAccountMeta(sysvar.ClockPubkey, false, false),
+		transaction.NewAccountMeta(params.AuthorizedPubkey, true, false),
+		transaction.NewAccountMeta(params.NewAuthorized, true, false),
+	}, buf.Bytes())
+}
+
+func newVoteInstruction(keys []*transaction.AccountMeta, data []byte) (transaction.ITransactionInstruction, error) {
+	return transaction.NewTransactionInstruction(ProgramId, keys, data)
+}
@@ -0,0 +1,290 @@
+package vote
+
+/*
+func：
+author： flynn
+date: 2020-08-03
+fork: https://github.com/solana-labs/solana-web3.js/src/vote-account.ts
+*/
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/JFJun/solana-go/account"
+	"github.com/JFJun/solana-go/rpc"
+)
+
+/*
+VoteStateVersions的序号
+*/
+const (
+	VoteStateVersionV0_23_5 uint32 = iota
+	VoteStateVersionV1_14_11
+	VoteStateVersionCurrent
+)
+
+/*
+prior voters环形缓冲区的容量
+*/
+const maxPriorVoters = 32
+
+/*
+Latency只在Current版本中有值
+*/
+type Lockout struct {
+	Slot              uint64
+	ConfirmationCount uint32
+	Latency           uint8
+}
+
+type AuthorizedVoter struct {
+	Epoch  uint64
+	Pubkey account.PublicKey
+}
+
+type PriorVoter struct {
+	Pubkey     account.PublicKey
+	EpochStart uint64
+	EpochEnd   uint64
+}
+
+type EpochCredits struct {
+	Epoch       uint64
+	Credits     uint64
+	PrevCredits uint64
+}
+
+type BlockTimestamp struct {
+	Slot      uint64
+	Timestamp int64
+}
+
+type VoteAccount struct {
+	Version              uint32
+	NodePubkey           account.PublicKey
+	AuthorizedWithdrawer account.PublicKey
+	Commission           uint8
+	Votes                []Lockout
+	RootSlot             *uint64
+	AuthorizedVoters     []AuthorizedVoter // 按epoch升序
+	PriorVoters          []PriorVoter      // 只包含有效的记录，按写入顺序
+	EpochCredits         []EpochCredits
+	LastTimestamp        BlockTimestamp
+}
+
+/*
+最近一次投票的slot，没有投票时返回false
+*/
+func (va *VoteAccount) LastVotedSlot() (uint64, bool) {
+	if len(va.Votes) == 0 {
+		return 0, false
+	}
+	return va.Votes[len(va.Votes)-1].Slot, true
+}
+
+/*
+指定epoch生效的voter：epoch不小于它的最大一条记录
+*/
+func (va *VoteAccount) AuthorizedVoter(epoch uint64) (account.PublicKey, bool) {
+	var (
+		voter account.PublicKey
+		found bool
+	)
+	for _, v := range va.AuthorizedVoters {
+		if v.Epoch > epoch {
+			break
+		}
+		voter, found = v.Pubkey, true
+	}
+	return voter, found
+}
+
+/*
+指定epoch获得的credits，用于判断验证者是否正常投票
+*/
+func (va *VoteAccount) EpochCreditsEarned(epoch uint64) (uint64, bool) {
+	for _, c := range va.EpochCredits {
+		if c.Epoch == epoch {
+			return c.Credits - c.PrevCredits, true
+		}
+	}
+	return 0, false
+}
+
+/*
+vote账户数据为bincode编码的VoteStateVersions，支持V0_23_5、V1_14_11和Current
+*/
+func DeserializeVoteAccount(data []byte) (*VoteAccount, error) {
+	r := &reader{data: data}
+	va := &VoteAccount{Version: r.u32()}
+	switch va.Version {
+	case VoteStateVersionV0_23_5:
+		va.NodePubkey = r.pubkey()
+		voter := r.pubkey()
+		voterEpoch := r.u64()
+		va.AuthorizedVoters = []AuthorizedVoter{{Epoch: voterEpoch, Pubkey: voter}}
+		// (pubkey, epoch start, epoch end, slot) * 32 | u64 idx
+		var buf []PriorVoter
+		for i := 0; i < maxPriorVoters; i++ {
+			buf = append(buf, PriorVoter{Pubkey: r.pubkey(), EpochStart: r.u64(), EpochEnd: r.u64()})
+			r.u64()
+		}
+		va.PriorVoters = orderPriorVoters(buf, r.u64(), false)
+		va.AuthorizedWithdrawer = r.pubkey()
+		va.Commission = r.u8()
+		va.Votes = r.lockouts(false)
+		va.RootSlot = r.optionU64()
+	case VoteStateVersionV1_14_11, VoteStateVersionCurrent:
+		va.NodePubkey = r.pubkey()
+		va.AuthorizedWithdrawer = r.pubkey()
+		va.Commission = r.u8()
+		va.Votes = r.lockouts(va.Version == VoteStateVersionCurrent)
+		va.RootSlot = r.optionU64()
+		count := r.length(8 + account.PublicKeySize)
+		for i := 0; i < count; i++ {
+			va.AuthorizedVoters = append(va.AuthorizedVoters, AuthorizedVoter{Epoch: r.u64(), Pubkey: r.pubkey()})
+		}
+		// (pubkey, epoch start, epoch end) * 32 | u64 idx | bool is empty
+		var buf []PriorVoter
+		for i := 0; i < maxPriorVoters; i++ {
+			buf = append(buf, PriorVoter{Pubkey: r.pubkey(), EpochStart: r.u64(), EpochEnd: r.u64()})
+		}
+		idx := r.u64()
+		va.PriorVoters = orderPriorVoters(buf, idx, r.u8() == 1)
+	default:
+		return nil, fmt.Errorf("vote state version [%d] is not supported", va.Version)
+	}
+	count := r.length(24)
+	for i := 0; i < count; i++ {
+		va.EpochCredits = append(va.EpochCredits, EpochCredits{Epoch: r.u64(), Credits: r.u64(), PrevCredits: r.u64()})
+	}
+	va.LastTimestamp = BlockTimestamp{Slot: r.u64(), Timestamp: int64(r.u64())}
+	if r.err != nil {
+		return nil, r.err
+	}
+	return va, nil
+}
+
+func GetVoteAccount(client *rpc.RpcClient, votePubkey account.PublicKey) (*VoteAccount, error) {
+	info, err := client.GetAccountInfo(votePubkey)
+	if err != nil {
+		return nil, err
+	}
+	if info == nil {
+		return nil, fmt.Errorf("vote account [%s] is not found", votePubkey.String())
+	}
+	if info.Owner != ProgramId {
+		return nil, fmt.Errorf("account [%s] is not owned by vote program", votePubkey.String())
+	}
+	return DeserializeVoteAccount(info.Data)
+}
+
+/*
+环形缓冲区：idx为最后写入的位置，从idx+1开始为最早的记录；全0的记录为未使用
+*/
+func orderPriorVoters(buf []PriorVoter, idx uint64, isEmpty bool) []PriorVoter {
+	if isEmpty || idx >= uint64(len(buf)) {
+		return nil
+	}
+	var voters []PriorVoter
+	for i := 1; i <= len(buf); i++ {
+		v := buf[(int(idx)+i)%len(buf)]
+		if v.Pubkey.IsZero() && v.EpochStart == 0 && v.EpochEnd == 0 {
+			continue
+		}
+		voters = append(voters, v)
+	}
+	return voters
+}
+
+/*
+顺序读取bincode数据，出错后后续读取都返回0，最后统一检查err
+*/
+type reader struct {
+	data   []byte
+	offset int
+	err    error
+}
+
+func (r *reader) read(n int) []byte {
+	if r.err != nil {
+		return make([]byte, n)
+	}
+	if len(r.data)-r.offset < n {
+		r.err = fmt.Errorf("vote account data is too short, need %d bytes at offset %d", n, r.offset)
+		return make([]byte, n)
+	}
+	b := r.data[r.offset : r.offset+n]
+	r.offset += n
+	return b
+}
+
+func (r *reader) u8() uint8 {
+	return r.read(1)[0]
+}
+
+func (r *reader) u32() uint32 {
+	return binary.LittleEndian.Uint32(r.read(4))
+}
+
+func (r *reader) u64() uint64 {
+	return binary.LittleEndian.Uint64(r.read(8))
+}
+
+func (r *reader) pubkey() account.PublicKey {
+	var pubkey account.PublicKey
+	copy(pubkey[:], r.read(account.PublicKeySize))
+	return pubkey
+}
+
+func (r *reader) optionU64() *uint64 {
+	switch r.u8() {
+	case 0:
+		return nil
+	case 1:
+		v := r.u64()
+		return &v
+	default:
+		if r.err == nil {
+			r.err = errors.New("option tag is not valid")
+		}
+		return nil
+	}
+}
+
+/*
+读取u64长度，并检查剩余数据足够，避免恶意长度导致大量分配
+*/
+func (r *reader) length(itemSize int) int {
+	n := r.u64()
+	if r.err != nil {
+		return 0
+	}
+	if n > uint64(len(r.data)-r.offset)/uint64(itemSize) {
+		r.err = fmt.Errorf("length %d at offset %d is out of range", n, r.offset)
+		return 0
+	}
+	return int(n)
+}
+
+/*
+Current版本的投票为LandedVote：u8 latency | u64 slot | u32 confirmation count
+*/
+func (r *reader) lockouts(landed bool) []Lockout {
+	itemSize := 12
+	if landed {
+		itemSize = 13
+	}
+	count := r.length(itemSize)
+	var votes []Lockout
+	for i := 0; i < count; i++ {
+		var lockout Lockout
+		if landed {
+			lockout.Latency = r.u8()
+		}
+		lockout.Slot = r.u64()
+		lockout.ConfirmationCount = r.u32()
+		votes = append(votes, lockout)
+	}
+	return votes
+}